@@ -0,0 +1,262 @@
+// Command mcts loads a search function from a Go plugin and runs a Monte-Carlo tree search with it.
+//
+// Usage:
+//
+//	mcts [flags] search.so
+//
+// The plugin must export a symbol named Search. See plugin.Load.
+//
+// Without -iters or -time the search runs until interrupted.
+// An interrupt stops the search gracefully and the report and -save tree are still written.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ajzaff/lazyq"
+	"github.com/ajzaff/mcts"
+	"github.com/ajzaff/mcts/perft"
+	"github.com/ajzaff/mcts/plugin"
+	"github.com/ajzaff/mcts/variation"
+)
+
+var (
+	iters         = flag.Int("iters", 0, "Maximum number of iterations (0 for no limit).")
+	timeLimit     = flag.Duration("time", 0, "Maximum duration of the search (0 for no limit).")
+	softTime      = flag.Duration("soft_time", 0, "Soft time limit for the time manager (0 disables the time manager).")
+	extend        = flag.Float64("extend", 0, "Fraction of -soft_time to extend the search by when the best action changes.")
+	seed          = flag.Uint64("seed", 1337, "Seed for the random source.")
+	exploreFactor = flag.Float64("explore", 2*math.Pi, "Explore factor of the PUCT formula.")
+	shuffle       = flag.Bool("shuffle", true, "Shuffle expanded actions before inserting them.")
+	discount      = flag.Float64("discount", 1, "Discount factor applied to bandit statistics on update.")
+	window        = flag.Float64("window", 0, "Sliding window of runs for bandit statistics (0 for no window).")
+	progress      = flag.Duration("progress", 0, "Print progress info lines at this interval (0 to disable).")
+	load          = flag.String("load", "", "Continue the search from a tree file written by -save.")
+	save          = flag.String("save", "", "Write the resulting tree to this file.")
+	format        = flag.String("format", "text", "Output format: text or json.")
+	config        = flag.String("config", "", "Comma separated key=value pairs passed to the plugin's Init.")
+	profile       = flag.Int("profile", 0, "Time the phases of every n-th iteration and report them (0 to disable).")
+)
+
+type childReport struct {
+	Action string  `json:"action"`
+	Runs   float32 `json:"runs"`
+	Value  float32 `json:"value"`
+	Score  float32 `json:"score"`
+}
+
+type report struct {
+	Iterations  int                `json:"iterations"`
+	Duration    time.Duration      `json:"duration_ns"`
+	Err         string             `json:"error,omitempty"`
+	MaxLine     []string           `json:"max_line"`
+	MinLine     []string           `json:"min_line"`
+	PopularLine []string           `json:"popular_line"`
+	Children    []childReport      `json:"children"`
+	Stats       perft.SearchStats  `json:"stats"`
+	Profile     *mcts.PhaseProfile `json:"profile,omitempty"`
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] search.so\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown -format %q\n", *format)
+		os.Exit(2)
+	}
+
+	p, err := plugin.Load(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := p.Init(parseConfig(*config)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if p.Name != "" {
+		fmt.Fprintf(os.Stderr, "loaded %s %s\n", p.Name, p.Version)
+	}
+
+	// Start with the plugin's preferred options and override them with flags set explicitly.
+	opts := p.Options()
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "iters":
+			opts = append(opts, mcts.MaxIters(*iters))
+		case "seed":
+			opts = append(opts, mcts.RandSource(rand.NewPCG(*seed, *seed)))
+		case "explore":
+			opts = append(opts, mcts.ExploreFactor(float32(*exploreFactor)))
+		case "shuffle":
+			opts = append(opts, mcts.ExpandShuffle(*shuffle))
+		case "discount":
+			opts = append(opts, mcts.Discount(float32(*discount)))
+		case "window":
+			opts = append(opts, mcts.SlidingWindow(float32(*window)))
+		}
+	})
+	switch {
+	case *softTime > 0:
+		opts = append(opts, mcts.TimeManager(mcts.TimeControl{Soft: *softTime, Hard: *timeLimit, Extend: *extend}))
+	case *timeLimit > 0:
+		opts = append(opts, mcts.DoneAfter(*timeLimit))
+	}
+	if *progress > 0 {
+		opts = append(opts, mcts.Progress(*progress, func(s mcts.Snapshot) {
+			fmt.Fprintf(os.Stderr, "info iters %d ips %.0f time %v best %q runs %g score %.4g pv %v\n",
+				s.Iterations, s.IterationsPerSecond, s.Elapsed.Round(time.Millisecond),
+				s.Best.Action, s.Best.Runs, s.Best.Stat.Score(), s.Line)
+		}))
+	}
+	if *profile > 0 {
+		opts = append(opts, mcts.ProfilePhases(*profile))
+	}
+	// Stop the search on interrupt and restore the default behavior so a second interrupt exits.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	opts = append(opts, mcts.Done(ctx.Done()))
+	if *load != "" {
+		root, err := readTree(*load)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		opts = append(opts, mcts.UseContinuation(root))
+	}
+
+	result := mcts.Search(p.Search, opts...)
+	stop()
+
+	if *save != "" {
+		if err := writeTree(*save, result.Root); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	r := makeReport(result)
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(r)
+	} else {
+		err = writeText(os.Stdout, r)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if result.Err != nil {
+		os.Exit(1)
+	}
+}
+
+func makeReport(result mcts.Result) report {
+	r := rand.New(rand.NewPCG(*seed, *seed))
+	rep := report{
+		Iterations:  result.Iterations,
+		Duration:    result.Duration,
+		MaxLine:     variation.Line(variation.MaxVariation(result.Root, r)),
+		MinLine:     variation.Line(variation.MinVariation(result.Root, r)),
+		PopularLine: variation.Line(variation.MostPopularVariation(result.Root, r)),
+		Stats:       perft.DetailedSearchStats(result.Root),
+		Profile:     result.Profile,
+	}
+	if result.Err != nil {
+		rep.Err = result.Err.Error()
+	}
+	for c := range lazyq.Payloads(result.Root.Queue) {
+		cr := childReport{Action: c.Action, Runs: c.Runs, Value: c.Value}
+		if c.Runs > 0 {
+			cr.Score = c.Stat.Score()
+		}
+		rep.Children = append(rep.Children, cr)
+	}
+	return rep
+}
+
+func writeText(w io.Writer, r report) error {
+	fmt.Fprintln(w, "iterations:   ", r.Iterations)
+	fmt.Fprintln(w, "duration:     ", r.Duration)
+	if r.Err != "" {
+		fmt.Fprintln(w, "error:        ", r.Err)
+	}
+	fmt.Fprintln(w, "max_line:     ", r.MaxLine)
+	fmt.Fprintln(w, "min_line:     ", r.MinLine)
+	fmt.Fprintln(w, "popular_line: ", r.PopularLine)
+	fmt.Fprintln(w, "node_count:   ", r.Stats.NodeCount)
+	fmt.Fprintln(w, "leaf_count:   ", r.Stats.LeafCount)
+	fmt.Fprintln(w, "exhausted:    ", r.Stats.ExhaustedCount)
+	fmt.Fprintln(w, "height:       ", r.Stats.Height)
+	fmt.Fprintln(w, "deepest_run:  ", r.Stats.DeepestRun)
+	if p := r.Profile; p != nil {
+		mean := p.Mean()
+		fmt.Fprintln(w, "sampled:      ", p.Sampled)
+		fmt.Fprintln(w, "select:       ", mean.Select)
+		fmt.Fprintln(w, "run:          ", mean.Run)
+		fmt.Fprintln(w, "expand:       ", mean.Expand)
+		fmt.Fprintln(w, "backprop:     ", mean.Backprop)
+		fmt.Fprintln(w, "path_length:  ", p.PathLength)
+		fmt.Fprintln(w, "expansions:   ", p.Expansions)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%-16s %12s %12s %12s\n", "action", "runs", "value", "score")
+	for _, c := range r.Children {
+		if _, err := fmt.Fprintf(w, "%-16q %12g %12g %12.4g\n", c.Action, c.Runs, c.Value, c.Score); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseConfig(s string) map[string]string {
+	config := make(map[string]string)
+	for kv := range strings.SplitSeq(s, ",") {
+		if kv == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(kv, "=")
+		config[k] = v
+	}
+	return config
+}
+
+func readTree(path string) (*mcts.Node, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return mcts.ReadTree(f)
+}
+
+func writeTree(path string, root *mcts.Node) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := mcts.WriteTree(f, root); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,58 @@
+// Command mctsdiff compares two saved search trees and reports their differences.
+//
+// Usage:
+//
+//	mctsdiff [flags] a.tree b.tree
+//
+// Trees are read with mcts.ReadTree, or mcts.DecodeJSON for files ending in .json.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ajzaff/mcts"
+	"github.com/ajzaff/mcts/perft"
+)
+
+var top = flag.Int("top", 20, "Number of score changes to report.")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] a.tree b.tree\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	a, err := readTree(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	b, err := readTree(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := perft.Compare(a, b, *top).WriteText(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func readTree(path string) (*mcts.Node, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if filepath.Ext(path) == ".json" {
+		return mcts.DecodeJSON(f)
+	}
+	return mcts.ReadTree(f)
+}
@@ -5,4 +5,5 @@ go 1.24.2
 require (
 	github.com/ajzaff/fastlog v0.0.0-20250504190535-7ae1b28d450a
 	github.com/ajzaff/lazyq v0.3.0
+	github.com/tetratelabs/wazero v1.9.0
 )
@@ -0,0 +1,171 @@
+package inspect
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ajzaff/lazyq"
+	"github.com/ajzaff/mcts"
+	"github.com/ajzaff/mcts/perft"
+	"github.com/ajzaff/mcts/variation"
+)
+
+type childInfo struct {
+	Action        string   `json:"action"`
+	Runs          float32  `json:"runs"`
+	Value         float32  `json:"value"`
+	Score         *float32 `json:"score,omitempty"`
+	ExploreFactor float32  `json:"explore_factor"`
+	Opened        bool     `json:"opened"`
+}
+
+type nodeInfo struct {
+	Line       []string      `json:"line"`
+	Iterations int           `json:"iterations,omitempty"`
+	Duration   time.Duration `json:"duration_ns,omitempty"`
+	Trials     float32       `json:"trials"`
+	Minimize   bool          `json:"minimize"`
+	Exhausted  bool          `json:"exhausted"`
+	Children   []childInfo   `json:"children"`
+}
+
+type pvInfo struct {
+	Max     []string `json:"max"`
+	Min     []string `json:"min"`
+	Popular []string `json:"popular"`
+}
+
+// histBin is a JSON friendly HistBin with Max formatted to allow infinities.
+type histBin struct {
+	Max   string `json:"max"`
+	Count int64  `json:"count"`
+}
+
+func makeNodeInfo(n *mcts.Node, line []string) nodeInfo {
+	info := nodeInfo{
+		Line:      line,
+		Trials:    n.Trials,
+		Minimize:  n.Minimize(),
+		Exhausted: n.Exhausted(),
+		Children:  []childInfo{},
+	}
+	for c := range lazyq.Payloads(n.Queue) {
+		ci := childInfo{
+			Action:        c.Action,
+			Runs:          c.Runs,
+			Value:         c.Value,
+			ExploreFactor: c.ExploreFactor,
+			Opened:        c.Node != nil,
+		}
+		if c.Runs > 0 {
+			score := c.Stat.Score()
+			ci.Score = &score
+		}
+		info.Children = append(info.Children, ci)
+	}
+	return info
+}
+
+// NewHandler returns an http.Handler serving JSON views of the search tree from src.
+//
+// The handler serves the following endpoints:
+//
+//	GET /root           Root statistics and children.
+//	GET /pv             Max, min and most popular principal variations.
+//	GET /stats          perft.DetailedSearchStats.
+//	GET /hist/runs      Histogram of child runs over perft.DefaultRunBins.
+//	GET /hist/score     Histogram of child scores over perft.DefaultScoreBins.
+//	GET /hist/priority  Histogram of child bandit priorities over perft.DefaultPriorityBins.
+//	GET /node/a/b/c     Statistics and children of the node reached by the line a, b, c.
+func NewHandler(src Source) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /root", func(w http.ResponseWriter, r *http.Request) {
+		var info nodeInfo
+		src.View(func(res mcts.Result) {
+			info = makeNodeInfo(res.Root, []string{})
+			info.Iterations = res.Iterations
+			info.Duration = res.Duration
+		})
+		writeJSON(w, info)
+	})
+	mux.HandleFunc("GET /pv", func(w http.ResponseWriter, r *http.Request) {
+		var info pvInfo
+		src.View(func(res mcts.Result) {
+			rng := rand.New(rand.NewPCG(0, 0))
+			info = pvInfo{
+				Max:     variation.Line(variation.MaxVariation(res.Root, rng)),
+				Min:     variation.Line(variation.MinVariation(res.Root, rng)),
+				Popular: variation.Line(variation.MostPopularVariation(res.Root, rng)),
+			}
+		})
+		writeJSON(w, info)
+	})
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, r *http.Request) {
+		var stats perft.SearchStats
+		src.View(func(res mcts.Result) { stats = perft.DetailedSearchStats(res.Root) })
+		writeJSON(w, stats)
+	})
+	mux.HandleFunc("GET /hist/{kind}", func(w http.ResponseWriter, r *http.Request) {
+		var (
+			maxes   []float64
+			valueFn func(mcts.Stat) float64
+		)
+		switch r.PathValue("kind") {
+		case "runs":
+			maxes, valueFn = perft.DefaultRunBins(), func(s mcts.Stat) float64 { return float64(s.Runs) }
+		case "score":
+			maxes, valueFn = perft.DefaultScoreBins(), func(s mcts.Stat) float64 { return float64(s.Score()) }
+		case "priority":
+			hist := perft.MakeHist(perft.DefaultPriorityBins())
+			src.View(func(res mcts.Result) { perft.FillPriority(res.Root, &hist) })
+			writeHist(w, hist)
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		hist := perft.MakeHist(maxes)
+		src.View(func(res mcts.Result) { perft.FillHist(res.Root, &hist, valueFn) })
+		writeHist(w, hist)
+	})
+	mux.HandleFunc("GET /node/{line...}", func(w http.ResponseWriter, r *http.Request) {
+		line := []string{}
+		if p := r.PathValue("line"); p != "" {
+			line = strings.Split(strings.TrimSuffix(p, "/"), "/")
+		}
+		var (
+			info  nodeInfo
+			found bool
+		)
+		src.View(func(res mcts.Result) {
+			if n := variation.Variation(res.Root, line...); n != nil {
+				info, found = makeNodeInfo(n, line), true
+			}
+		})
+		if !found {
+			http.Error(w, "node not found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, info)
+	})
+	return mux
+}
+
+func writeHist(w http.ResponseWriter, hist perft.Hist[float64]) {
+	bins := make([]histBin, len(hist.Bins))
+	for i, b := range hist.Bins {
+		bins[i] = histBin{Max: strconv.FormatFloat(b.Max, 'g', -1, 64), Count: b.Count}
+	}
+	writeJSON(w, bins)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
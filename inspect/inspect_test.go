@@ -0,0 +1,87 @@
+package inspect
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ajzaff/mcts"
+)
+
+func testSearch(c *mcts.Context) {
+	if c.Len() < 3 {
+		c.Expand("a", "b")
+	}
+	c.SetResultValue(1)
+}
+
+func get(t *testing.T, h http.Handler, path string, v any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+	if rec.Code == http.StatusOK && v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("GET %s: got invalid JSON: %v\n%s", path, err, rec.Body)
+		}
+	}
+	return rec.Code
+}
+
+func TestHandlerStatic(t *testing.T) {
+	h := NewHandler(Static(mcts.Search(testSearch, mcts.MaxIters(50))))
+
+	var root nodeInfo
+	if code := get(t, h, "/root", &root); code != http.StatusOK {
+		t.Fatalf("GET /root: got status %d, want 200", code)
+	}
+	if len(root.Children) != 2 || root.Iterations != 50 {
+		t.Errorf("GET /root: got %d children and %d iterations, want 2 and 50", len(root.Children), root.Iterations)
+	}
+
+	var node nodeInfo
+	if code := get(t, h, "/node/a/b", &node); code != http.StatusOK {
+		t.Fatalf("GET /node/a/b: got status %d, want 200", code)
+	}
+	if len(node.Line) != 2 || node.Line[1] != "b" {
+		t.Errorf("GET /node/a/b: got line %v, want [a b]", node.Line)
+	}
+	if code := get(t, h, "/node/x", nil); code != http.StatusNotFound {
+		t.Errorf("GET /node/x: got status %d, want 404", code)
+	}
+
+	var bins []histBin
+	if code := get(t, h, "/hist/runs", &bins); code != http.StatusOK || len(bins) == 0 {
+		t.Errorf("GET /hist/runs: got status %d with %d bins, want 200 with bins", code, len(bins))
+	}
+	for _, path := range []string{"/pv", "/stats", "/hist/score", "/hist/priority"} {
+		if code := get(t, h, path, nil); code != http.StatusOK {
+			t.Errorf("GET %s: got status %d, want 200", path, code)
+		}
+	}
+}
+
+func TestHandlerLive(t *testing.T) {
+	live := NewLive()
+	h := NewHandler(live)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		live.Finish(mcts.Search(testSearch, mcts.MaxIters(2000), mcts.Observe(live)))
+	}()
+	for range 10 {
+		if code := get(t, h, "/stats", nil); code != http.StatusOK {
+			t.Errorf("GET /stats: got status %d, want 200", code)
+		}
+	}
+	wg.Wait()
+
+	var root nodeInfo
+	get(t, h, "/root", &root)
+	if root.Iterations != 2000 {
+		t.Errorf("GET /root: got %d iterations after Finish, want 2000", root.Iterations)
+	}
+}
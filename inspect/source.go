@@ -0,0 +1,107 @@
+// Package inspect serves search trees over HTTP for inspection while searches run.
+package inspect
+
+import (
+	"sync"
+
+	"github.com/ajzaff/mcts"
+)
+
+// Source provides synchronized access to a search tree.
+//
+// mcts.Ponderer implements Source.
+type Source interface {
+	// View calls fn with the current Result while the tree is not being modified.
+	//
+	// fn must not retain the tree after returning.
+	View(fn func(mcts.Result))
+}
+
+// Static returns a Source for the Result of a finished search.
+func Static(r mcts.Result) Source { return staticSource{r} }
+
+type staticSource struct{ r mcts.Result }
+
+func (s staticSource) View(fn func(mcts.Result)) { fn(s.r) }
+
+// Live is a Source for a search running with mcts.Search.
+//
+// Pass Live to mcts.Observe. Views are run on the search goroutine between iterations.
+// Call Finish with the Result when the search returns.
+type Live struct {
+	mcts.NopObserver
+
+	views chan func()
+
+	mu       sync.Mutex
+	root     *mcts.Node
+	iters    int
+	finished *mcts.Result
+	done     chan struct{}
+}
+
+// NewLive returns a new Live source.
+func NewLive() *Live {
+	return &Live{
+		views: make(chan func()),
+		done:  make(chan struct{}),
+	}
+}
+
+// OnSelect records the root of the search.
+func (l *Live) OnSelect(frontier *mcts.Node, _ []string) {
+	if l.root == nil {
+		for l.root = frontier; l.root.Parent != nil; l.root = l.root.Parent {
+		}
+	}
+}
+
+// OnIteration runs pending views.
+func (l *Live) OnIteration(i int) {
+	l.iters = i + 1
+	for {
+		select {
+		case fn := <-l.views:
+			fn()
+		default:
+			return
+		}
+	}
+}
+
+// View implements Source.
+//
+// View blocks until the search completes its current iteration.
+func (l *Live) View(fn func(mcts.Result)) {
+	l.mu.Lock()
+	if r := l.finished; r != nil {
+		l.mu.Unlock()
+		fn(*r)
+		return
+	}
+	l.mu.Unlock()
+
+	ran := make(chan struct{})
+	view := func() {
+		fn(mcts.Result{Root: l.root, Iterations: l.iters})
+		close(ran)
+	}
+	select {
+	case l.views <- view:
+		<-ran
+	case <-l.done:
+		l.View(fn)
+	}
+}
+
+// Finish marks the search as finished with the Result r.
+//
+// Subsequent views are served from r.
+func (l *Live) Finish(r mcts.Result) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.finished == nil {
+		l.finished = &r
+		close(l.done)
+	}
+}
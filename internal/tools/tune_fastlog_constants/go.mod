@@ -8,4 +8,6 @@ require (
 	github.com/ajzaff/mcts v0.0.0-20250512213343-b7e9359b5c47
 )
 
-require github.com/ajzaff/lazyq v0.2.0 // indirect
+require github.com/ajzaff/lazyq v0.3.0 // indirect
+
+replace github.com/ajzaff/mcts => ../../..
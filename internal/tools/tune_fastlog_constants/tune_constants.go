@@ -10,7 +10,6 @@ import (
 	"github.com/ajzaff/fastlog/suite"
 	"github.com/ajzaff/mcts"
 	"github.com/ajzaff/mcts/perft"
-	"github.com/ajzaff/mcts/variation"
 )
 
 const kLo, kHi = -2, 2
@@ -70,13 +69,13 @@ func main() {
 		c.Expand("lo", "hi")
 	}, mcts.RandSource(r), mcts.MaxIters(1_000))
 
-	maxNode := perft.Max(result.Root, func(n *mcts.Node, stat mcts.Stat) float32 {
+	best, _ := perft.ArgMax(result.Root, func(n *mcts.Node, stat mcts.Stat) float32 {
 		return stat.Score()
 	})
 
-	line := variation.Line(maxNode)
+	line := best.Line
 
-	fmt.Println(variation.Stat(maxNode))
+	fmt.Println(best.Stat)
 	fmt.Println(line)
 	fmt.Println()
 
@@ -0,0 +1,173 @@
+package mcts
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"slices"
+
+	"github.com/ajzaff/lazyq"
+)
+
+// jsonNode is the JSON representation of a node and the Stat leading to it.
+//
+// Stat fields are omitted on the root. Node fields are omitted on unopened children.
+type jsonNode struct {
+	Action        string      `json:"action"`
+	Runs          *float32    `json:"runs,omitempty"`
+	Value         *float32    `json:"value,omitempty"`
+	Score         *float32    `json:"score,omitempty"`
+	ExploreFactor *float32    `json:"explore_factor,omitempty"`
+	Opened        bool        `json:"opened"`
+	Trials        float32     `json:"trials,omitempty"`
+	Minimize      bool        `json:"minimize,omitempty"`
+	Exhausted     bool        `json:"exhausted,omitempty"`
+	Children      []*jsonNode `json:"children,omitempty"`
+}
+
+// JSONEncoder writes search trees as JSON to an output stream.
+//
+// Nodes are written as they are visited so the document is never built in memory.
+type JSONEncoder struct {
+	w *bufio.Writer
+
+	// MaxDepth limits the depth of nodes written below root.
+	// The default 0 writes all depths.
+	MaxDepth int
+	// MinRuns drops children with fewer than MinRuns runs.
+	MinRuns float32
+	// TopK keeps only the K children with the most runs per node in descending order of runs.
+	// The default 0 keeps all children in queue order.
+	TopK int
+}
+
+// NewJSONEncoder returns a new JSONEncoder that writes to w.
+func NewJSONEncoder(w io.Writer) *JSONEncoder { return &JSONEncoder{w: bufio.NewWriter(w)} }
+
+// Encode writes the JSON encoding of the tree under root followed by a newline.
+func (e *JSONEncoder) Encode(root *Node) error {
+	if err := e.encodeNode(root.Action, root, nil, 0); err != nil {
+		return err
+	}
+	if err := e.w.WriteByte('\n'); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+func (e *JSONEncoder) children(n *Node) []Child {
+	var children []Child
+	for c := range lazyq.Payloads(n.Queue) {
+		if c.Runs >= e.MinRuns {
+			children = append(children, c)
+		}
+	}
+	if e.TopK > 0 && len(children) > e.TopK {
+		slices.SortStableFunc(children, func(a, b Child) int {
+			switch {
+			case a.Runs > b.Runs:
+				return -1
+			case a.Runs < b.Runs:
+				return +1
+			}
+			return 0
+		})
+		children = children[:e.TopK]
+	}
+	return children
+}
+
+func (e *JSONEncoder) encodeNode(action string, n *Node, stat *Stat, depth int) error {
+	v := jsonNode{Action: action}
+	if stat != nil {
+		v.Runs, v.Value, v.ExploreFactor = &stat.Runs, &stat.Value, &stat.ExploreFactor
+		if stat.Runs > 0 {
+			score := stat.Score()
+			v.Score = &score
+		}
+	}
+	var children []Child
+	// Unopened children only carry their action and Stat.
+	if n != nil {
+		v.Opened = true
+		v.Trials = n.Trials
+		v.Minimize = n.Minimize()
+		v.Exhausted = n.Exhausted()
+		if e.MaxDepth == 0 || depth < e.MaxDepth {
+			children = e.children(n)
+		}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(children) == 0 {
+		_, err := e.w.Write(b)
+		return err
+	}
+	// Stream children into the object in place of its closing brace.
+	e.w.Write(b[:len(b)-1])
+	e.w.WriteString(`,"children":[`)
+	for i, c := range children {
+		if i > 0 {
+			e.w.WriteByte(',')
+		}
+		stat := c.Stat
+		if err := e.encodeNode(c.Action, c.Node, &stat, depth+1); err != nil {
+			return err
+		}
+	}
+	_, err = e.w.WriteString("]}")
+	return err
+}
+
+// DecodeJSON reads a tree written by JSONEncoder from r and returns its root.
+//
+// Children dropped by the encoder's filters are missing from the tree.
+// Opened children are placed before unopened children as in the bandit queue.
+func DecodeJSON(r io.Reader) (*Node, error) {
+	var v jsonNode
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return nil, err
+	}
+	root := newRoot()
+	v.decode(root)
+	return root, nil
+}
+
+func (v *jsonNode) decode(n *Node) {
+	n.Action = v.Action
+	n.Trials = v.Trials
+	if v.Minimize {
+		n.Flags |= FlagsMinimize
+	}
+	if v.Exhausted {
+		n.Flags |= FlagsExhausted
+	}
+	lazyq.Grow(&n.Queue, len(v.Children))
+	var opened int
+	for _, opening := range []bool{true, false} {
+		for _, c := range v.Children {
+			if c.Opened != opening {
+				continue
+			}
+			child := Child{Action: c.Action}
+			if c.Runs != nil {
+				child.Runs = *c.Runs
+			}
+			if c.Value != nil {
+				child.Value = *c.Value
+			}
+			if c.ExploreFactor != nil {
+				child.ExploreFactor = *c.ExploreFactor
+			}
+			if c.Opened {
+				child.Node = &Node{Parent: n}
+				c.decode(child.Node)
+				opened++
+			}
+			n.Queue.AppendMax(child)
+		}
+	}
+	n.restoreOpened(opened)
+}
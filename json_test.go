@@ -0,0 +1,56 @@
+package mcts
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ajzaff/lazyq"
+)
+
+func TestJSONEncodeDecode(t *testing.T) {
+	results := Search(func(c *Context) {
+		if c.Len() < 3 {
+			c.Expand("a", "b", "c")
+		}
+		c.SetResultValue(float32(len(c.ActionAt(0))))
+	}, MaxIters(100))
+
+	var buf bytes.Buffer
+	if err := NewJSONEncoder(&buf).Encode(results.Root); err != nil {
+		t.Fatalf("Encode(): got err = %v, want nil", err)
+	}
+	root, err := DecodeJSON(&buf)
+	if err != nil {
+		t.Fatalf("DecodeJSON(): got err = %v, want nil", err)
+	}
+	compareTrees(t, nil, root, results.Root)
+}
+
+func TestJSONEncodeFilters(t *testing.T) {
+	results := Search(func(c *Context) {
+		if c.Len() < 3 {
+			c.Expand("a", "b", "c")
+		}
+		c.SetResultValue(1)
+	}, MaxIters(100))
+
+	var buf bytes.Buffer
+	e := NewJSONEncoder(&buf)
+	e.MaxDepth = 1
+	e.TopK = 2
+	if err := e.Encode(results.Root); err != nil {
+		t.Fatalf("Encode(): got err = %v, want nil", err)
+	}
+	root, err := DecodeJSON(&buf)
+	if err != nil {
+		t.Fatalf("DecodeJSON(): got err = %v, want nil", err)
+	}
+	if got := root.Queue.Len(); got != 2 {
+		t.Errorf("DecodeJSON(): got %d root children, want 2", got)
+	}
+	for c := range lazyq.Payloads(root.Queue) {
+		if c.Node != nil && c.Node.Queue.Len() != 0 {
+			t.Errorf("DecodeJSON(): child %q got %d children past MaxDepth, want 0", c.Action, c.Node.Queue.Len())
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package mcts
 
 import (
+	"fmt"
 	"iter"
 	"math/rand/v2"
 	"testing"
+	"time"
 
 	"github.com/ajzaff/lazyq"
 )
@@ -142,3 +144,167 @@ func extractStat(root *Node, line ...string) Stat {
 	}
 	return s.E.Stat
 }
+
+func TestSearchDiscountSlidingWindow(t *testing.T) {
+	const window = 8
+
+	results := Search(func(c *Context) {
+		if c.Len() < 3 {
+			c.Expand("a", "b")
+		}
+		c.AddResultValue(float32(c.Len()))
+	}, MaxIters(200), Discount(0.9), SlidingWindow(window))
+
+	var check func(n *Node)
+	check = func(n *Node) {
+		var sum float32
+		for e := range lazyq.Payloads(n.Queue) {
+			if e.Runs > window {
+				t.Errorf("TestSearchDiscountSlidingWindow(): got Runs = %v, want <= %v", e.Runs, window)
+			}
+			sum += e.Runs
+			if e.Node != nil {
+				check(e.Node)
+			}
+		}
+		if d := n.Trials - sum; d < -1e-3 || d > 1e-3 {
+			t.Errorf("TestSearchDiscountSlidingWindow(): got Trials = %v, want sum of child Runs = %v", n.Trials, sum)
+		}
+	}
+	check(results.Root)
+}
+
+func TestSearchDiscountDrift(t *testing.T) {
+	const drift = 300
+
+	// switchIter returns the first iteration after which "b" is the most visited root child.
+	// "a" is the better action until the rewards swap at drift iterations.
+	switchIter := func(gamma float32) int {
+		var iters int
+		switched := -1
+		Search(func(c *Context) {
+			if c.Len() == 0 {
+				c.Expand("a", "b")
+				return
+			}
+			iters++
+			if (c.ActionAt(0) == "a") == (iters < drift) {
+				c.SetResultValue(1)
+			} else {
+				c.SetResultValue(0)
+			}
+		}, MaxIters(3*drift), Discount(gamma), ProgressIters(10, func(s Snapshot) {
+			if switched < 0 && s.Best.Action == "b" {
+				switched = s.Iterations
+			}
+		}))
+		return switched
+	}
+
+	discounted, plain := switchIter(0.95), switchIter(1)
+	if discounted < drift || discounted > drift+50 {
+		t.Errorf("TestSearchDiscountDrift(): got preferred child switch at %d, want within 50 iterations after drift at %d", discounted, drift)
+	}
+	if plain >= 0 && plain <= discounted {
+		t.Errorf("TestSearchDiscountDrift(): got switch at %d without discount, want later than %d with discount", plain, discounted)
+	}
+}
+
+func BenchmarkSearchDiscount(b *testing.B) {
+	for _, gamma := range []float32{1, 0.99} {
+		b.Run(fmt.Sprintf("gamma=%v", gamma), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				Search(func(c *Context) {
+					if c.Len() < 3 {
+						c.Expand("a", "b", "c", "d", "e", "f", "g", "h")
+					}
+					c.SetResultValue(float32(c.Len() % 2))
+				}, MaxIters(10_000), Discount(gamma))
+			}
+		})
+	}
+}
+
+func TestSearchProgressIters(t *testing.T) {
+	var snapshots []Snapshot
+	Search(func(c *Context) {
+		if c.Len() < 5 {
+			c.Expand("a", "b")
+		}
+		c.SetResultValue(1)
+	}, MaxIters(100), ProgressIters(25, func(s Snapshot) { snapshots = append(snapshots, s) }))
+
+	if got, want := len(snapshots), 4; got != want {
+		t.Fatalf("TestSearchProgressIters(): got %d snapshots, want %d", got, want)
+	}
+	for i, s := range snapshots {
+		if want := 25 * (i + 1); s.Iterations != want {
+			t.Errorf("TestSearchProgressIters(): snapshot %d got %d iterations, want %d", i, s.Iterations, want)
+		}
+		if len(s.Line) == 0 || s.Line[0] != s.Best.Action {
+			t.Errorf("TestSearchProgressIters(): snapshot %d got line %v, want line starting with best action %q", i, s.Line, s.Best.Action)
+		}
+	}
+}
+
+func TestSearchProfilePhases(t *testing.T) {
+	var pathLength, expansions int
+	result := Search(func(c *Context) {
+		pathLength += c.Len()
+		if c.Len() < 5 {
+			c.Expand("a", "b")
+			expansions += 2
+		}
+		time.Sleep(time.Microsecond)
+		c.SetResultValue(1)
+	}, MaxIters(100), ProfilePhases(10))
+
+	p := result.Profile
+	if p == nil {
+		t.Fatalf("TestSearchProfilePhases(): got nil Profile, want profile")
+	}
+	if p.Iterations != 100 || p.Sampled != 10 {
+		t.Errorf("TestSearchProfilePhases(): got %d iterations with %d sampled, want 100 with 10 sampled", p.Iterations, p.Sampled)
+	}
+	if want := float64(pathLength) / 100; p.PathLength != want {
+		t.Errorf("TestSearchProfilePhases(): got PathLength = %v, want %v", p.PathLength, want)
+	}
+	if want := float64(expansions) / 100; p.Expansions != want {
+		t.Errorf("TestSearchProfilePhases(): got Expansions = %v, want %v", p.Expansions, want)
+	}
+	if p.Sum.Run < 10*time.Microsecond {
+		t.Errorf("TestSearchProfilePhases(): got Sum.Run = %v, want at least %v", p.Sum.Run, 10*time.Microsecond)
+	}
+	if total := p.Sum.Total(); total > result.Duration {
+		t.Errorf("TestSearchProfilePhases(): got Sum.Total() = %v, want at most Duration = %v", total, result.Duration)
+	}
+	if mean := p.Mean(); mean.Run != p.Sum.Run/10 {
+		t.Errorf("TestSearchProfilePhases(): got Mean().Run = %v, want %v", mean.Run, p.Sum.Run/10)
+	}
+
+	if result := Search(func(c *Context) { c.SetResultValue(1) }, MaxIters(1)); result.Profile != nil {
+		t.Errorf("TestSearchProfilePhases(): got Profile = %v without ProfilePhases, want nil", result.Profile)
+	}
+}
+
+func TestSearchTimeManagerStopsEarly(t *testing.T) {
+	const maxIters = 1000
+
+	results := Search(func(c *Context) {
+		c.Expand("a", "b")
+		if c.ActionAt(0) == "a" {
+			c.SetResultValue(1)
+		} else {
+			c.SetResultValue(0)
+		}
+	}, MaxIters(maxIters), ExploreFactor(0.1), TimeManager(TimeControl{}))
+
+	if results.Iterations >= maxIters {
+		t.Errorf("TestSearchTimeManagerStopsEarly(): got %d iterations, want fewer than %d", results.Iterations, maxIters)
+	}
+	a, b := extractStat(results.Root, "a").Runs, extractStat(results.Root, "b").Runs
+	if gap, remaining := a-b, float32(maxIters-results.Iterations); gap <= remaining {
+		t.Errorf("TestSearchTimeManagerStopsEarly(): got visit gap %v, want more than remaining iterations %v", gap, remaining)
+	}
+}
@@ -0,0 +1,169 @@
+// Package metrics renders search statistics in the Prometheus text exposition format.
+//
+// See https://prometheus.io/docs/instrumenting/exposition_formats/.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ajzaff/mcts"
+	"github.com/ajzaff/mcts/inspect"
+	"github.com/ajzaff/mcts/perft"
+)
+
+// Collector writes metrics in the text exposition format to w.
+type Collector func(w io.Writer) error
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, +1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func writeHeader(w io.Writer, name, help, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func writeGauge(w io.Writer, name, help string, v float64) {
+	writeHeader(w, name, help, "gauge")
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(v))
+}
+
+func writeCounter(w io.Writer, name, help string, v float64) {
+	writeHeader(w, name, help, "counter")
+	fmt.Fprintf(w, "%s %s\n", name, formatFloat(v))
+}
+
+// WriteSearchStats writes the SearchStats s as gauges named with the given prefix, e.g. "mcts".
+func WriteSearchStats(w io.Writer, prefix string, s perft.SearchStats) error {
+	bw := bufio.NewWriter(w)
+	writeGauge(bw, prefix+"_tree_nodes", "Number of nodes in the search tree.", float64(s.NodeCount))
+	writeGauge(bw, prefix+"_tree_leaves", "Number of leaf nodes in the search tree.", float64(s.LeafCount))
+	writeGauge(bw, prefix+"_tree_exhausted_nodes", "Number of exhausted nodes in the search tree.", float64(s.ExhaustedCount))
+	writeGauge(bw, prefix+"_tree_height", "Height of the search tree.", float64(s.Height))
+	writeGauge(bw, prefix+"_tree_deepest_run", "Depth of the deepest node with runs.", float64(s.DeepestRun))
+	return bw.Flush()
+}
+
+// WriteHist writes h as a histogram metric with the given name and help text.
+//
+// Bucket counts are made cumulative and a +Inf bucket is added when missing.
+func WriteHist[T perft.Number](w io.Writer, name, help string, h perft.Hist[T]) error {
+	bw := bufio.NewWriter(w)
+	writeHeader(bw, name, help, "histogram")
+	var count int64
+	for _, b := range h.Bins {
+		count += b.Count
+		le := float64(b.Max)
+		if math.IsInf(le, +1) {
+			continue // Written below.
+		}
+		fmt.Fprintf(bw, "%s_bucket{le=%q} %d\n", name, formatFloat(le), count)
+	}
+	count += h.Overflow
+	fmt.Fprintf(bw, "%s_bucket{le=\"+Inf\"} %d\n", name, count)
+	fmt.Fprintf(bw, "%s_sum %s\n", name, formatFloat(h.Sum))
+	fmt.Fprintf(bw, "%s_count %d\n", name, count)
+	return bw.Flush()
+}
+
+// Counters is an mcts.Observer counting events of a running search.
+//
+// Pass Counters to mcts.Observe. Counters is safe to collect while the search runs.
+type Counters struct {
+	mcts.NopObserver
+
+	start      atomic.Int64 // Unix nanoseconds of the first iteration.
+	iterations atomic.Int64
+	expanded   atomic.Int64
+	backprops  atomic.Int64
+	depth      atomic.Int64
+}
+
+func (c *Counters) OnSelect(_ *mcts.Node, path []string) {
+	c.start.CompareAndSwap(0, time.Now().UnixNano())
+	c.depth.Add(int64(len(path)))
+}
+
+func (c *Counters) OnExpand(_ *mcts.Node, actions []string, _ []float32) {
+	c.expanded.Add(int64(len(actions)))
+}
+
+func (c *Counters) OnBackprop(*mcts.Node, float32, float32) { c.backprops.Add(1) }
+
+func (c *Counters) OnIteration(int) { c.iterations.Add(1) }
+
+// Collector returns a Collector writing the counters as metrics named with the given prefix.
+func (c *Counters) Collector(prefix string) Collector {
+	return func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		iters := c.iterations.Load()
+		writeCounter(bw, prefix+"_search_iterations_total", "Number of search iterations.", float64(iters))
+		writeCounter(bw, prefix+"_search_expanded_actions_total", "Number of actions expanded.", float64(c.expanded.Load()))
+		writeCounter(bw, prefix+"_search_backprops_total", "Number of node updates during backpropagation.", float64(c.backprops.Load()))
+		writeCounter(bw, prefix+"_search_selected_depth_total", "Sum of the depths of selected frontier nodes.", float64(c.depth.Load()))
+		var ips float64
+		if start := c.start.Load(); start != 0 {
+			if d := time.Since(time.Unix(0, start)); d > 0 {
+				ips = float64(iters) / d.Seconds()
+			}
+		}
+		writeGauge(bw, prefix+"_search_iterations_per_second", "Average iterations per second since the search started.", ips)
+		return bw.Flush()
+	}
+}
+
+// TreeCollector returns a Collector writing tree statistics and the run and score histograms of src.
+//
+// Metrics are named with the given prefix.
+func TreeCollector(src inspect.Source, prefix string) Collector {
+	return func(w io.Writer) error {
+		var (
+			stats     perft.SearchStats
+			runHist   = perft.MakeHist(perft.DefaultRunBins())
+			scoreHist = perft.MakeHist(perft.DefaultScoreBins())
+		)
+		src.View(func(r mcts.Result) {
+			stats = perft.DetailedSearchStats(r.Root)
+			perft.FillHist(r.Root, &runHist, func(s mcts.Stat) float64 { return float64(s.Runs) })
+			// Unrun children have a score of -Inf and are excluded.
+			perft.FillHist(r.Root, &scoreHist, func(s mcts.Stat) float64 {
+				if s.Runs == 0 {
+					return math.NaN()
+				}
+				return float64(s.Score())
+			})
+		})
+		if err := WriteSearchStats(w, prefix, stats); err != nil {
+			return err
+		}
+		if err := WriteHist(w, prefix+"_child_runs", "Distribution of child runs.", runHist); err != nil {
+			return err
+		}
+		return WriteHist(w, prefix+"_child_score", "Distribution of child scores.", scoreHist)
+	}
+}
+
+// NewHandler returns an http.Handler serving the metrics written by collectors.
+func NewHandler(collectors ...Collector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		for _, c := range collectors {
+			if err := c(w); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+	})
+}
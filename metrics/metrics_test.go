@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ajzaff/mcts"
+	"github.com/ajzaff/mcts/inspect"
+	"github.com/ajzaff/mcts/perft"
+)
+
+func TestWriteHist(t *testing.T) {
+	h := perft.MakeHist([]int64{1, 2, 4})
+	for _, x := range []int64{0, 1, 2, 3, 4} {
+		h.Insert(x)
+	}
+	var sb strings.Builder
+	if err := WriteHist(&sb, "x", "Test histogram.", h); err != nil {
+		t.Fatalf("WriteHist(): got err = %v, want nil", err)
+	}
+	want := `# HELP x Test histogram.
+# TYPE x histogram
+x_bucket{le="1"} 2
+x_bucket{le="2"} 3
+x_bucket{le="4"} 5
+x_bucket{le="+Inf"} 5
+x_sum 10
+x_count 5
+`
+	if got := sb.String(); got != want {
+		t.Errorf("WriteHist(): got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCollectors(t *testing.T) {
+	var c Counters
+	r := mcts.Search(func(ctx *mcts.Context) {
+		if ctx.Len() < 2 {
+			ctx.Expand("a", "b")
+		}
+		ctx.SetResultValue(1)
+	}, mcts.MaxIters(10), mcts.Observe(&c))
+
+	var sb strings.Builder
+	for _, collect := range []Collector{c.Collector("mcts"), TreeCollector(inspect.Static(r), "mcts")} {
+		if err := collect(&sb); err != nil {
+			t.Fatalf("Collector(): got err = %v, want nil", err)
+		}
+	}
+	for _, want := range []string{"mcts_search_iterations_total 10\n", "mcts_tree_nodes ", `mcts_child_score_bucket{le="+Inf"}`} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("Collector(): got output missing %q:\n%s", want, sb.String())
+		}
+	}
+}
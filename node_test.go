@@ -1,6 +1,7 @@
 package mcts
 
 import (
+	"math"
 	"testing"
 
 	"github.com/ajzaff/lazyq"
@@ -28,3 +29,35 @@ func TestJustInTimeNodeAllocation(t *testing.T) {
 		t.Errorf("TestJustInTimeNodeAllocation(): expected child to be allocated after next() but it was nil")
 	}
 }
+
+func TestPriority(t *testing.T) {
+	var n Node
+	n.NewChild("a", 1)
+	n.NewChild("b", 1)
+
+	if got := n.Priority(lazyq.FirstMaxElem(n.Queue)); !math.IsInf(float64(got), +1) {
+		t.Errorf("TestPriority(): got priority %v for unopened child, want +Inf", got)
+	}
+
+	// The child selected last at the root holds the priority the search stored after its backprop.
+	var last string
+	res := Search(func(c *Context) {
+		if c.Len() < 2 {
+			c.Expand("a", "b", "c")
+		}
+		if c.Len() > 0 {
+			last = c.ActionAt(0)
+		}
+		c.SetResultValue(float32(c.Len() % 2))
+	}, MaxIters(50))
+	for e := range lazyq.Elements(res.Root.Queue) {
+		if e.E.Action != last {
+			continue
+		}
+		if got := res.Root.Priority(e.E); got != e.Priority {
+			t.Errorf("TestPriority(): got priority %v for %q, want stored heap priority %v", got, last, e.Priority)
+		}
+		return
+	}
+	t.Fatalf("TestPriority(): got no root child %q", last)
+}
@@ -0,0 +1,28 @@
+package mcts
+
+// Observer receives callbacks for each phase of a search iteration.
+//
+// Slices passed to an Observer are owned by the search and are only valid for the duration of the call.
+// Observers are called on the search goroutine and should return quickly.
+type Observer interface {
+	// OnSelect is called after the frontier node is selected with the actions leading to it from root.
+	OnSelect(frontier *Node, path []string)
+	// OnExpand is called after node is expanded with actions and their optional priors.
+	//
+	// OnExpand is not called when no actions were added.
+	OnExpand(node *Node, actions []string, priors []float32)
+	// OnBackprop is called after the result value and count have been added to the selected child of node.
+	OnBackprop(node *Node, value, count float32)
+	// OnIteration is called at the end of iteration i starting from 0.
+	OnIteration(i int)
+}
+
+// NopObserver implements Observer with no-op callbacks.
+//
+// Embed NopObserver to implement only a subset of Observer methods.
+type NopObserver struct{}
+
+func (NopObserver) OnSelect(*Node, []string)            {}
+func (NopObserver) OnExpand(*Node, []string, []float32) {}
+func (NopObserver) OnBackprop(*Node, float32, float32)  {}
+func (NopObserver) OnIteration(int)                     {}
@@ -0,0 +1,64 @@
+package mcts
+
+import (
+	"fmt"
+	"testing"
+)
+
+type countingObserver struct {
+	NopObserver
+	errs       []string // Failed invariants reported after the search.
+	selects    int
+	expands    int
+	backprops  int
+	iterations int
+}
+
+func (o *countingObserver) OnSelect(frontier *Node, path []string) {
+	o.selects++
+	if got := len(path); got > 0 && frontier.Action != path[got-1] {
+		o.errs = append(o.errs, fmt.Sprintf("OnSelect(): got frontier %q, want last action of path %v", frontier.Action, path))
+	}
+}
+
+func (o *countingObserver) OnExpand(*Node, []string, []float32) { o.expands++ }
+
+func (o *countingObserver) OnBackprop(*Node, float32, float32) { o.backprops++ }
+
+func (o *countingObserver) OnIteration(i int) {
+	if i != o.iterations {
+		o.errs = append(o.errs, fmt.Sprintf("OnIteration(): got iteration %d, want %d", i, o.iterations))
+	}
+	o.iterations++
+}
+
+func TestObserver(t *testing.T) {
+	const maxIters = 50
+
+	var o countingObserver
+	var wantBackprops int
+	results := Search(func(c *Context) {
+		wantBackprops += c.Len()
+		if c.Len() < 4 {
+			c.Expand("a", "b", "c")
+		}
+		c.SetResultValue(1)
+	}, MaxIters(maxIters), Observe(&o))
+
+	for _, err := range o.errs {
+		t.Errorf("TestObserver(): %s", err)
+	}
+
+	if o.iterations != results.Iterations {
+		t.Errorf("TestObserver(): got %d iterations, want %d", o.iterations, results.Iterations)
+	}
+	if o.selects != maxIters {
+		t.Errorf("TestObserver(): got %d selects, want %d", o.selects, maxIters)
+	}
+	if o.expands == 0 || o.expands > maxIters {
+		t.Errorf("TestObserver(): got %d expands, want in (0, %d]", o.expands, maxIters)
+	}
+	if o.backprops != wantBackprops {
+		t.Errorf("TestObserver(): got %d backprops, want %d", o.backprops, wantBackprops)
+	}
+}
@@ -3,6 +3,7 @@ package mcts
 import (
 	"math"
 	"math/rand/v2"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,7 +13,13 @@ type searchOptions struct {
 	continuation  *Node
 	expandShuffle bool
 	exploreFactor float32
-	done          bool
+	discount      float32
+	window        float32
+	observers     []Observer
+	progress      []*progress
+	timeManager   *timeManager
+	profiler      *profiler
+	done          atomic.Bool
 }
 
 func newSearchOptions() *searchOptions {
@@ -20,6 +27,7 @@ func newSearchOptions() *searchOptions {
 		src:           rand.NewPCG(1337, 0xBEEF),
 		expandShuffle: true,
 		exploreFactor: 2 * math.Pi,
+		discount:      1,
 	}
 }
 
@@ -29,7 +37,7 @@ func Done(done <-chan struct{}) Option {
 	return Option(func(opts *searchOptions) {
 		go func() {
 			<-done
-			opts.done = true
+			opts.done.Store(true)
 		}()
 	})
 }
@@ -37,7 +45,7 @@ func DoneAfter(d time.Duration) Option {
 	return Option(func(opts *searchOptions) {
 		go func() {
 			<-time.After(d)
-			opts.done = true
+			opts.done.Store(true)
 		}()
 	})
 }
@@ -56,3 +64,62 @@ func ExpandShuffle(expandShuffle bool) Option {
 func ExploreFactor(exploreFactor float32) Option {
 	return Option(func(opts *searchOptions) { opts.exploreFactor = exploreFactor })
 }
+
+// Discount sets the discount factor gamma applied to the Runs and Value of every child of a node
+// each time one of them is updated, as in discounted UCB.
+//
+// Values of gamma below 1 favor recent results over old ones, which is useful when
+// the rewards returned by Func drift over time. Unselected children decay too, so their
+// exploration bonus grows back. Discounting costs a pass over the children of each updated node.
+// The default 1 disables discounting.
+func Discount(gamma float32) Option {
+	return Option(func(opts *searchOptions) { opts.discount = gamma })
+}
+
+// SlidingWindow limits the Runs of each bandit to approximately the most recent n runs.
+//
+// When an update would exceed n runs, Runs and Value are rescaled to n runs keeping the mean score.
+// The default 0 disables the window.
+func SlidingWindow(n float32) Option {
+	return Option(func(opts *searchOptions) { opts.window = n })
+}
+
+// Observe adds an Observer which is called during each phase of the search.
+//
+// Observe may be passed multiple times. Observers are called in the order they were added.
+func Observe(o Observer) Option {
+	return Option(func(opts *searchOptions) { opts.observers = append(opts.observers, o) })
+}
+
+// Progress calls fn with a Snapshot of the search about every d.
+//
+// Progress is checked once per iteration so fn may be called less often if iterations are slow.
+func Progress(d time.Duration, fn func(Snapshot)) Option {
+	return Option(func(opts *searchOptions) {
+		opts.progress = append(opts.progress, &progress{every: d, fn: fn})
+	})
+}
+
+// ProgressIters calls fn with a Snapshot of the search every n iterations.
+func ProgressIters(n int, fn func(Snapshot)) Option {
+	return Option(func(opts *searchOptions) {
+		opts.progress = append(opts.progress, &progress{iters: n, fn: fn})
+	})
+}
+
+// TimeManager enables smart time management with the given TimeControl.
+//
+// In addition to the time limits, the search stops early once the most visited root child
+// cannot be overtaken in the remaining iterations (see MaxIters) or time.
+func TimeManager(tc TimeControl) Option {
+	return Option(func(opts *searchOptions) { opts.timeManager = &timeManager{TimeControl: tc, soft: tc.Soft} })
+}
+
+// ProfilePhases records a PhaseProfile of the search in Result.Profile.
+//
+// The phases of every n-th iteration are timed while path lengths and expansions are counted
+// on every iteration. Sampling keeps the overhead low enough to leave profiling enabled;
+// n <= 1 times every iteration.
+func ProfilePhases(n int) Option {
+	return Option(func(opts *searchOptions) { opts.profiler = &profiler{every: max(n, 1)} })
+}
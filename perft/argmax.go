@@ -0,0 +1,142 @@
+package perft
+
+import (
+	"container/heap"
+	"slices"
+
+	"github.com/ajzaff/lazyq"
+	"github.com/ajzaff/mcts"
+	"github.com/ajzaff/mcts/variation"
+)
+
+// Choice is a child selected from a tree by ArgMin, ArgMax, TopK or BottomK.
+type Choice struct {
+	// Child is the selected child entry.
+	//
+	// Child.Node is nil when the child was never opened.
+	mcts.Child
+
+	// Parent is the node whose queue holds Child.
+	Parent *mcts.Node
+
+	// Line is the full line from the root up to and including Child.Action.
+	Line []string
+
+	// Value is the value returned by valueFn for Child.
+	Value float32
+}
+
+// ArgMax returns the child in the tree under root maximizing valueFn.
+//
+// All children are considered including unopened ones.
+// Ties are broken in favor of the child found first in depth-first pre-order.
+// Children for which valueFn returns NaN are skipped.
+// ArgMax returns false if no child was found.
+func ArgMax(root *mcts.Node, valueFn func(*mcts.Node, mcts.Stat) float32) (Choice, bool) {
+	top := TopK(root, 1, valueFn)
+	if len(top) == 0 {
+		return Choice{}, false
+	}
+	return top[0], true
+}
+
+// ArgMin returns the child in the tree under root minimizing valueFn.
+//
+// ArgMin otherwise follows the same rules as ArgMax.
+func ArgMin(root *mcts.Node, valueFn func(*mcts.Node, mcts.Stat) float32) (Choice, bool) {
+	bottom := BottomK(root, 1, valueFn)
+	if len(bottom) == 0 {
+		return Choice{}, false
+	}
+	return bottom[0], true
+}
+
+// TopK returns up to k children in the tree under root with the greatest values of valueFn.
+//
+// The result is sorted in descending order of value.
+// Ties are broken in favor of the child found first in depth-first pre-order.
+// Children for which valueFn returns NaN are skipped.
+func TopK(root *mcts.Node, k int, valueFn func(*mcts.Node, mcts.Stat) float32) []Choice {
+	return selectK(root, k, valueFn, func(a, b float32) bool { return a > b })
+}
+
+// BottomK returns up to k children in the tree under root with the least values of valueFn.
+//
+// The result is sorted in ascending order of value.
+// BottomK otherwise follows the same rules as TopK.
+func BottomK(root *mcts.Node, k int, valueFn func(*mcts.Node, mcts.Stat) float32) []Choice {
+	return selectK(root, k, valueFn, func(a, b float32) bool { return a < b })
+}
+
+// selectK selects the k best children according to better in a single pass using a bounded heap.
+func selectK(root *mcts.Node, k int, valueFn func(*mcts.Node, mcts.Stat) float32, better func(a, b float32) bool) []Choice {
+	if root == nil || k <= 0 {
+		return nil
+	}
+	h := &choiceHeap{better: better}
+	var seq int
+	for n := range NodeSeq(root) {
+		for c := range lazyq.Payloads(n.Queue) {
+			v := valueFn(n, c.Stat)
+			if v != v { // NaN.
+				continue
+			}
+			e := choiceEntry{Choice: Choice{Child: c, Parent: n, Value: v}, seq: seq}
+			seq++
+			if h.Len() < k {
+				heap.Push(h, e)
+				continue
+			}
+			if h.worse(h.entries[0], e) {
+				h.entries[0] = e
+				heap.Fix(h, 0)
+			}
+		}
+	}
+	entries := h.entries
+	slices.SortFunc(entries, func(a, b choiceEntry) int {
+		switch {
+		case h.worse(b, a):
+			return -1
+		case h.worse(a, b):
+			return +1
+		default:
+			return 0
+		}
+	})
+	choices := make([]Choice, len(entries))
+	for i, e := range entries {
+		e.Line = append(variation.Line(e.Parent), e.Action)
+		choices[i] = e.Choice
+	}
+	return choices
+}
+
+type choiceEntry struct {
+	Choice
+	seq int
+}
+
+// choiceHeap keeps the worst retained choice at the top.
+type choiceHeap struct {
+	entries []choiceEntry
+	better  func(a, b float32) bool
+}
+
+// worse reports whether a ranks below b, breaking ties by later traversal order.
+func (h *choiceHeap) worse(a, b choiceEntry) bool {
+	if a.Value != b.Value {
+		return h.better(b.Value, a.Value)
+	}
+	return a.seq > b.seq
+}
+
+func (h choiceHeap) Len() int           { return len(h.entries) }
+func (h choiceHeap) Less(i, j int) bool { return h.worse(h.entries[i], h.entries[j]) }
+func (h choiceHeap) Swap(i, j int)      { h.entries[i], h.entries[j] = h.entries[j], h.entries[i] }
+func (h *choiceHeap) Push(x any)        { h.entries = append(h.entries, x.(choiceEntry)) }
+func (h *choiceHeap) Pop() any {
+	e := h.entries[len(h.entries)-1]
+	h.entries = h.entries[:len(h.entries)-1]
+	return e
+}
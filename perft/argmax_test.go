@@ -0,0 +1,116 @@
+package perft
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/ajzaff/lazyq"
+	"github.com/ajzaff/mcts"
+	"github.com/ajzaff/mcts/variation"
+)
+
+func argmaxTree() *mcts.Node {
+	return mcts.Search(func(c *mcts.Context) {
+		if c.Len() < 2 {
+			c.Expand("a", "b", "c")
+		}
+		var v float32
+		for i, a := range c.Actions2() {
+			v += float32(i+1) * float32(a[0]-'a')
+		}
+		c.SetResultValue(v)
+	}, mcts.MaxIters(300)).Root
+}
+
+// linearK is a reference implementation of TopK using a stable sort.
+func linearK(root *mcts.Node, k int, valueFn func(*mcts.Node, mcts.Stat) float32, desc bool) []Choice {
+	var all []Choice
+	for n := range NodeSeq(root) {
+		for c := range lazyq.Payloads(n.Queue) {
+			if v := valueFn(n, c.Stat); !math.IsNaN(float64(v)) {
+				all = append(all, Choice{Child: c, Parent: n, Line: append(variation.Line(n), c.Action), Value: v})
+			}
+		}
+	}
+	slices.SortStableFunc(all, func(a, b Choice) int {
+		if desc {
+			a, b = b, a
+		}
+		switch {
+		case a.Value < b.Value:
+			return -1
+		case a.Value > b.Value:
+			return +1
+		default:
+			return 0
+		}
+	})
+	return all[:min(k, len(all))]
+}
+
+func sameChoices(a, b []Choice) bool {
+	return slices.EqualFunc(a, b, func(a, b Choice) bool {
+		return a.Parent == b.Parent && a.Node == b.Node && a.Action == b.Action && a.Value == b.Value && slices.Equal(a.Line, b.Line)
+	})
+}
+
+func TestTopKBottomK(t *testing.T) {
+	root := argmaxTree()
+	for name, valueFn := range map[string]func(*mcts.Node, mcts.Stat) float32{
+		"runs":  func(_ *mcts.Node, s mcts.Stat) float32 { return s.Runs },
+		"value": func(_ *mcts.Node, s mcts.Stat) float32 { return s.Value },
+		"tie":   func(*mcts.Node, mcts.Stat) float32 { return 0 },
+		"nan": func(_ *mcts.Node, s mcts.Stat) float32 {
+			if s.Runs < 10 {
+				return float32(math.NaN())
+			}
+			return s.Runs
+		},
+	} {
+		for _, k := range []int{0, 1, 2, 5, 100} {
+			if got, want := TopK(root, k, valueFn), linearK(root, k, valueFn, true); !sameChoices(got, want) {
+				t.Errorf("TopK(%s, %d): got %v, want %v", name, k, got, want)
+			}
+			if got, want := BottomK(root, k, valueFn), linearK(root, k, valueFn, false); !sameChoices(got, want) {
+				t.Errorf("BottomK(%s, %d): got %v, want %v", name, k, got, want)
+			}
+		}
+	}
+}
+
+func TestArgMax(t *testing.T) {
+	root := argmaxTree()
+	runs := func(_ *mcts.Node, s mcts.Stat) float32 { return s.Runs }
+
+	c, ok := ArgMax(root, runs)
+	if !ok {
+		t.Fatalf("ArgMax(): got ok = false, want true")
+	}
+	// The most visited child is one of the root's children, and its line holds only its own action.
+	if c.Parent != root || !slices.Equal(c.Line, []string{c.Action}) {
+		t.Errorf("ArgMax(): got line %v under parent %v, want [%q] under root", c.Line, variation.Line(c.Parent), c.Action)
+	}
+	if got := variation.Stat(root, c.Line...); got != c.Stat {
+		t.Errorf("ArgMax(): got stat %v, want stat of line %v", c.Stat, got)
+	}
+	if c.Node == nil || c.Node.Parent != root {
+		t.Errorf("ArgMax(): got node %v, want opened child of root", c.Node)
+	}
+
+	c, ok = ArgMin(root, runs)
+	if !ok {
+		t.Fatalf("ArgMin(): got ok = false, want true")
+	}
+	// The least visited child may be unopened, so look it up from its parent.
+	if e, ok := variation.LookupElem(c.Parent, c.Action); !ok || e.E.Stat != c.Stat {
+		t.Errorf("ArgMin(): got stat %v, want stat of %q under parent %v", c.Stat, c.Action, variation.Line(c.Parent))
+	}
+	if want := append(variation.Line(c.Parent), c.Action); !slices.Equal(c.Line, want) {
+		t.Errorf("ArgMin(): got line %v, want %v", c.Line, want)
+	}
+
+	if _, ok := ArgMax(&mcts.Node{}, runs); ok {
+		t.Errorf("ArgMax(empty): got ok = true, want false")
+	}
+}
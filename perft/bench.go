@@ -0,0 +1,288 @@
+package perft
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/ajzaff/lazyq"
+	"github.com/ajzaff/mcts"
+)
+
+// Problem is a search problem with a known optimal root action.
+type Problem interface {
+	// Name identifies the problem in reports.
+	Name() string
+	// Func returns the search function for a single trial using r for any randomness.
+	Func(r *rand.Rand) mcts.Func
+	// Value returns the true value of the root action.
+	Value(action string) float64
+	// Best returns an optimal root action.
+	Best() string
+}
+
+// BenchResult summarizes the quality of searches on a Problem with a fixed iteration budget.
+type BenchResult struct {
+	Problem string
+	Budget  int
+	Trials  int
+	// SimpleRegret is the mean difference in value between the best action and the most visited root action.
+	SimpleRegret float64
+	// BestRate is the fraction of trials where the most visited root action had the best value.
+	BestRate            float64
+	IterationsPerSecond float64
+}
+
+// Bench runs trials searches on each problem for each iteration budget and reports the quality of the results.
+//
+// Each trial uses a different random source. opts are passed to every search.
+func Bench(problems []Problem, budgets []int, trials int, opts ...mcts.Option) []BenchResult {
+	var results []BenchResult
+	for _, p := range problems {
+		best := p.Value(p.Best())
+		for _, budget := range budgets {
+			res := BenchResult{Problem: p.Name(), Budget: budget, Trials: trials}
+			var (
+				iters   int
+				elapsed time.Duration
+			)
+			for i := range trials {
+				seed := uint64(i)
+				trialOpts := append([]mcts.Option{mcts.RandSource(rand.NewPCG(seed, 0xBE7C))}, opts...)
+				trialOpts = append(trialOpts, mcts.MaxIters(budget))
+				r := mcts.Search(p.Func(rand.New(rand.NewPCG(seed, 0xF00D))), trialOpts...)
+				iters += r.Iterations
+				elapsed += r.Duration
+
+				v := p.Value(mostVisited(r.Root))
+				res.SimpleRegret += best - v
+				if v == best {
+					res.BestRate++
+				}
+			}
+			res.SimpleRegret /= float64(trials)
+			res.BestRate /= float64(trials)
+			if elapsed > 0 {
+				res.IterationsPerSecond = float64(iters) / elapsed.Seconds()
+			}
+			results = append(results, res)
+		}
+	}
+	return results
+}
+
+// mostVisited returns the root action with the most runs.
+func mostVisited(root *mcts.Node) string {
+	var (
+		action string
+		runs   = float32(math.Inf(-1))
+	)
+	for c := range lazyq.Payloads(root.Queue) {
+		if c.Runs > runs {
+			action, runs = c.Action, c.Runs
+		}
+	}
+	return action
+}
+
+// Bandit is a multi-armed bandit Problem with Bernoulli arms of the given means.
+type Bandit []float64
+
+func (b Bandit) Name() string { return "bandit" + strconv.Itoa(len(b)) }
+
+func (b Bandit) Func(r *rand.Rand) mcts.Func {
+	actions := make([]string, len(b))
+	for i := range b {
+		actions[i] = strconv.Itoa(i)
+	}
+	return func(c *mcts.Context) {
+		if c.Len() == 0 {
+			c.Expand(actions...)
+			c.SetResult(0, 0)
+			return
+		}
+		arm, _ := strconv.Atoi(c.ActionAt(0))
+		var v float32
+		if r.Float64() < b[arm] {
+			v = 1
+		}
+		c.SetResultValue(v)
+	}
+}
+
+func (b Bandit) Value(action string) float64 {
+	arm, err := strconv.Atoi(action)
+	if err != nil || arm < 0 || arm >= len(b) {
+		return math.Inf(-1)
+	}
+	return b[arm]
+}
+
+func (b Bandit) Best() string {
+	best := 0
+	for i, m := range b {
+		if m > b[best] {
+			best = i
+		}
+	}
+	return strconv.Itoa(best)
+}
+
+// PGame is a random two-player game tree with win or loss leaves and a known minimax value.
+//
+// The root player maximizes and players alternate. Leaf values are derived from Seed.
+// Frontier nodes are evaluated with a single uniformly random playout.
+type PGame struct {
+	Branching int
+	Depth     int
+	Seed      uint64
+}
+
+func (g PGame) Name() string {
+	return "pgame" + strconv.Itoa(g.Branching) + "x" + strconv.Itoa(g.Depth)
+}
+
+func (g PGame) actions() []string {
+	actions := make([]string, g.Branching)
+	for i := range actions {
+		actions[i] = strconv.Itoa(i)
+	}
+	return actions
+}
+
+// leaf returns the value of the leaf reached by line.
+func (g PGame) leaf(line []string) float64 {
+	h := fnv.New64a()
+	h.Write(strconv.AppendUint(nil, g.Seed, 10))
+	for _, a := range line {
+		h.Write([]byte{'/'})
+		h.Write([]byte(a))
+	}
+	return float64(h.Sum64() & 1)
+}
+
+// minimax returns the minimax value of the node reached by line.
+func (g PGame) minimax(line []string) float64 {
+	if len(line) == g.Depth {
+		return g.leaf(line)
+	}
+	maximize := len(line)%2 == 0
+	v := math.Inf(+1)
+	if maximize {
+		v = math.Inf(-1)
+	}
+	for _, a := range g.actions() {
+		x := g.minimax(append(line, a))
+		if maximize {
+			v = max(v, x)
+		} else {
+			v = min(v, x)
+		}
+	}
+	return v
+}
+
+func (g PGame) Func(r *rand.Rand) mcts.Func {
+	actions := g.actions()
+	var line []string
+	return func(c *mcts.Context) {
+		line = line[:0]
+		for a := range c.Actions() {
+			line = append(line, a)
+		}
+		if len(line)%2 == 1 {
+			c.Minimize()
+		} else {
+			c.Maximize()
+		}
+		if len(line) < g.Depth {
+			c.Expand(actions...)
+		}
+		// Random playout to a leaf.
+		for len(line) < g.Depth {
+			line = append(line, actions[r.IntN(len(actions))])
+		}
+		c.SetResultValue(float32(g.leaf(line)))
+	}
+}
+
+func (g PGame) Value(action string) float64 { return g.minimax([]string{action}) }
+
+func (g PGame) Best() string {
+	best, v := "", math.Inf(-1)
+	for _, a := range g.actions() {
+		if x := g.Value(a); x > v {
+			best, v = a, x
+		}
+	}
+	return best
+}
+
+// Trap is a deceptive two-move Problem.
+//
+// The root player chooses between "safe" with value Safe and "trap" where the opponent
+// chooses among Replies replies which all win for the root player except one.
+// Random playouts favor "trap" while its minimax value is 0.
+type Trap struct {
+	Safe    float64
+	Replies int
+}
+
+func (t Trap) Name() string { return "trap" + strconv.Itoa(t.Replies) }
+
+func (t Trap) Func(r *rand.Rand) mcts.Func {
+	replies := make([]string, t.Replies)
+	for i := range replies {
+		replies[i] = "r" + strconv.Itoa(i)
+	}
+	return func(c *mcts.Context) {
+		switch c.Len() {
+		case 0:
+			c.Maximize()
+			c.Expand("safe", "trap")
+			c.SetResult(0, 0)
+		case 1:
+			if c.ActionAt(0) == "safe" {
+				c.Maximize()
+				c.SetResultValue(float32(t.Safe))
+				return
+			}
+			c.Minimize()
+			c.Expand(replies...)
+			// Random playout of the opponent's reply.
+			var v float32
+			if r.IntN(t.Replies) != 0 {
+				v = 1
+			}
+			c.SetResultValue(v)
+		default:
+			c.Maximize()
+			var v float32
+			if c.ActionAt(1) != "r0" {
+				v = 1
+			}
+			c.SetResultValue(v)
+		}
+	}
+}
+
+func (t Trap) Value(action string) float64 {
+	if action == "safe" {
+		return t.Safe
+	}
+	return 0
+}
+
+func (t Trap) Best() string { return "safe" }
+
+// DefaultProblems returns a catalog of benchmark problems.
+func DefaultProblems() []Problem {
+	return []Problem{
+		Bandit{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.85, 0.9},
+		PGame{Branching: 3, Depth: 6, Seed: 1},
+		PGame{Branching: 2, Depth: 10, Seed: 2},
+		Trap{Safe: 0.5, Replies: 5},
+	}
+}
@@ -0,0 +1,29 @@
+package perft
+
+import (
+	"testing"
+)
+
+func TestBenchBandit(t *testing.T) {
+	results := Bench([]Problem{Bandit{0.1, 0.9}}, []int{200}, 10)
+	if len(results) != 1 {
+		t.Fatalf("Bench(): got %d results, want 1", len(results))
+	}
+	if r := results[0]; r.BestRate < 0.9 || r.SimpleRegret > 0.1 {
+		t.Errorf("Bench(): got best rate %v and regret %v, want >= 0.9 and <= 0.1", r.BestRate, r.SimpleRegret)
+	}
+}
+
+func BenchmarkQuality(b *testing.B) {
+	for _, p := range DefaultProblems() {
+		b.Run(p.Name(), func(b *testing.B) {
+			var r BenchResult
+			for range b.N {
+				r = Bench([]Problem{p}, []int{1000}, 10)[0]
+			}
+			b.ReportMetric(r.SimpleRegret, "regret")
+			b.ReportMetric(r.BestRate, "best_rate")
+			b.ReportMetric(r.IterationsPerSecond, "iters/s")
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package perft
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/ajzaff/lazyq"
+	"github.com/ajzaff/mcts"
+	"github.com/ajzaff/mcts/variation"
+)
+
+// ScoreChange records the score of a child reached by Line in two trees.
+type ScoreChange struct {
+	Line   []string
+	ScoreA float32
+	ScoreB float32
+}
+
+// Delta returns ScoreB - ScoreA.
+func (c ScoreChange) Delta() float32 { return c.ScoreB - c.ScoreA }
+
+// Comparison reports the differences between two search trees A and B.
+type Comparison struct {
+	// LineA and LineB are the most popular principal variations.
+	LineA, LineB []string
+	// Diverge is the index of the first action where LineA and LineB differ,
+	// or the length of the shorter line if one is a prefix of the other.
+	Diverge int
+	// TotalVariation is the total variation distance between the root visit distributions in [0, 1].
+	TotalVariation float64
+	// KL is the Kullback-Leibler divergence KL(A||B) of the root visit distributions in nats.
+	//
+	// Root actions missing from B are smoothed to avoid infinite divergence.
+	KL float64
+	// ProfileA and ProfileB are the per-depth profiles of each tree.
+	ProfileA, ProfileB Profile
+	// TopChanges are the children present in both trees with runs whose scores moved the most.
+	TopChanges []ScoreChange
+}
+
+// klEpsilon smooths the root visit distributions for KL.
+const klEpsilon = 1e-9
+
+// Compare compares the trees under a and b keeping the top n score changes.
+func Compare(a, b *mcts.Node, n int) Comparison {
+	// Break ties with the same random draws for both lines so identical trees have identical lines.
+	c := Comparison{
+		LineA:    variation.Line(variation.MostPopularVariation(a, rand.New(rand.NewPCG(0, 0)))),
+		LineB:    variation.Line(variation.MostPopularVariation(b, rand.New(rand.NewPCG(0, 0)))),
+		ProfileA: DepthProfile(a),
+		ProfileB: DepthProfile(b),
+	}
+	for c.Diverge < min(len(c.LineA), len(c.LineB)) && c.LineA[c.Diverge] == c.LineB[c.Diverge] {
+		c.Diverge++
+	}
+	c.TotalVariation, c.KL = rootDivergence(a, b)
+	compareScores(a, b, nil, &c.TopChanges)
+	slices.SortStableFunc(c.TopChanges, func(x, y ScoreChange) int {
+		return cmp.Compare(math.Abs(float64(y.Delta())), math.Abs(float64(x.Delta())))
+	})
+	if len(c.TopChanges) > n {
+		c.TopChanges = c.TopChanges[:n]
+	}
+	return c
+}
+
+func rootVisits(n *mcts.Node) (map[string]float64, float64) {
+	visits := make(map[string]float64)
+	var total float64
+	for c := range lazyq.Payloads(n.Queue) {
+		visits[c.Action] += float64(c.Runs)
+		total += float64(c.Runs)
+	}
+	return visits, total
+}
+
+func rootDivergence(a, b *mcts.Node) (tv, kl float64) {
+	va, ta := rootVisits(a)
+	vb, tb := rootVisits(b)
+	if ta == 0 || tb == 0 {
+		return 0, 0
+	}
+	actions := make(map[string]struct{})
+	for k := range va {
+		actions[k] = struct{}{}
+	}
+	for k := range vb {
+		actions[k] = struct{}{}
+	}
+	for k := range actions {
+		p, q := va[k]/ta, vb[k]/tb
+		tv += math.Abs(p - q)
+		if p > 0 {
+			kl += p * math.Log(p/max(q, klEpsilon))
+		}
+	}
+	return tv / 2, kl
+}
+
+func compareScores(a, b *mcts.Node, line []string, changes *[]ScoreChange) {
+	for ca := range lazyq.Payloads(a.Queue) {
+		e, ok := variation.LookupElem(b, ca.Action)
+		if !ok {
+			continue
+		}
+		cb := e.E
+		childLine := append(line[:len(line):len(line)], ca.Action)
+		if ca.Runs > 0 && cb.Runs > 0 {
+			*changes = append(*changes, ScoreChange{Line: childLine, ScoreA: a.Score(ca.Stat), ScoreB: b.Score(cb.Stat)})
+		}
+		if ca.Node != nil && cb.Node != nil {
+			compareScores(ca.Node, cb.Node, childLine, changes)
+		}
+	}
+}
+
+// WriteText writes the Comparison to w as a human readable report.
+func (c Comparison) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, "line_a:          ", c.LineA)
+	fmt.Fprintln(w, "line_b:          ", c.LineB)
+	fmt.Fprintln(w, "diverge:         ", c.Diverge)
+	fmt.Fprintf(w, "total_variation:  %.4f\n", c.TotalVariation)
+	fmt.Fprintf(w, "kl:               %.4f\n", c.KL)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%5s %12s %12s %12s\n", "depth", "nodes_a", "nodes_b", "delta")
+	for d := range max(len(c.ProfileA), len(c.ProfileB)) {
+		var na, nb int64
+		if d < len(c.ProfileA) {
+			na = c.ProfileA[d].NodeCount
+		}
+		if d < len(c.ProfileB) {
+			nb = c.ProfileB[d].NodeCount
+		}
+		fmt.Fprintf(w, "%5d %12d %12d %+12d\n", d, na, nb, nb-na)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%12s %12s %12s  %s\n", "score_a", "score_b", "delta", "line")
+	for _, s := range c.TopChanges {
+		if _, err := fmt.Fprintf(w, "%12.4g %12.4g %+12.4g  %v\n", s.ScoreA, s.ScoreB, s.Delta(), s.Line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package perft
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/ajzaff/mcts"
+)
+
+func TestCompare(t *testing.T) {
+	search := func(best string) *mcts.Node {
+		return mcts.Search(func(c *mcts.Context) {
+			if c.Len() < 2 {
+				c.Expand("a", "b")
+			}
+			var v float32
+			if c.ActionAt(0) == best {
+				v = 1
+			}
+			c.SetResultValue(v)
+		}, mcts.MaxIters(200), mcts.ExploreFactor(0.5)).Root
+	}
+	a, b := search("a"), search("b")
+
+	if c := Compare(a, a, 5); c.TotalVariation != 0 || c.KL != 0 || c.Diverge != len(c.LineA) {
+		t.Errorf("Compare(a, a): got tv = %v, kl = %v, diverge = %d, want 0, 0, %d", c.TotalVariation, c.KL, c.Diverge, len(c.LineA))
+	}
+
+	c := Compare(a, b, 5)
+	if c.Diverge != 0 {
+		t.Errorf("Compare(a, b): got diverge = %d, want 0 for lines %v and %v", c.Diverge, c.LineA, c.LineB)
+	}
+	if c.TotalVariation <= 0.5 || c.KL <= 0 {
+		t.Errorf("Compare(a, b): got tv = %v, kl = %v, want tv > 0.5 and kl > 0", c.TotalVariation, c.KL)
+	}
+	if len(c.TopChanges) == 0 || len(c.TopChanges) > 5 {
+		t.Fatalf("Compare(a, b): got %d changes, want in [1, 5]", len(c.TopChanges))
+	}
+	if d := c.TopChanges[0].Delta(); d < 0.5 && d > -0.5 {
+		t.Errorf("Compare(a, b): got top change %v, want |delta| >= 0.5", c.TopChanges[0])
+	}
+}
+
+func TestCompareSelfTiedVisits(t *testing.T) {
+	for seed := range uint64(20) {
+		// Constant rewards leave many children with tied visits.
+		root := mcts.Search(func(c *mcts.Context) {
+			if c.Len() < 6 {
+				c.Expand("a", "b", "c", "d")
+			}
+			c.SetResultValue(1)
+		}, mcts.MaxIters(300), mcts.RandSource(rand.NewPCG(seed, seed))).Root
+
+		if c := Compare(root, root, 1); c.Diverge != len(c.LineA) || len(c.LineA) != len(c.LineB) {
+			t.Errorf("Compare(t, t): seed %d got diverge = %d for lines %v and %v, want %d", seed, c.Diverge, c.LineA, c.LineB, len(c.LineA))
+		}
+	}
+}
@@ -1,8 +1,14 @@
 package perft
 
 import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
 	"math"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/ajzaff/lazyq"
 	"github.com/ajzaff/mcts"
@@ -24,16 +30,34 @@ func DefaultRunBins() []float64      { return slices.Clone(runBins) }
 func DefaultScoreBins() []float64    { return slices.Clone(scoreBins) }
 func DefaultPriorityBins() []float64 { return slices.Clone(priorityBins) }
 
-type HistBin[T int64 | float32] struct {
+// Number is the set of value types supported by Hist.
+type Number interface {
+	int64 | float32 | float64
+}
+
+// HistBin counts the values of a Hist falling in the bin.
+//
+// Bins are upper-inclusive: bin i counts values x where Bins[i-1].Max < x <= Bins[i].Max.
+// The first bin counts all values x <= Bins[0].Max.
+type HistBin[T Number] struct {
 	Max   T
 	Count int64
 }
 
-type Hist[T int64 | float32] struct {
+// Hist is a histogram over bins sorted by ascending Max.
+type Hist[T Number] struct {
 	Bins []HistBin[T]
+	// Overflow counts values greater than the Max of the last bin.
+	//
+	// Overflow is always 0 when the last bin is +Inf.
+	Overflow int64
+	// Sum is the sum of finite values inserted into the Hist.
+	Sum float64
+	// Infinite counts the ±Inf values in the Hist. They are counted in their bins but left out of Sum.
+	Infinite int64
 }
 
-func MakeHist[T int64 | float32](bins []T) Hist[T] {
+func MakeHist[T Number](bins []T) Hist[T] {
 	b := make([]HistBin[T], len(bins))
 	for i, v := range bins {
 		b[i].Max = v
@@ -41,7 +65,15 @@ func MakeHist[T int64 | float32](bins []T) Hist[T] {
 	return Hist[T]{Bins: b}
 }
 
-func Fill[T int64 | float32](root *mcts.Node, hist Hist[T], valueFn func(mcts.Stat) T) {
+// Fill inserts the value of every child under root into the bins of hist.
+//
+// hist is passed by value, so only the shared bin counts are updated. Use FillHist to update Overflow and Sum.
+func Fill[T Number](root *mcts.Node, hist Hist[T], valueFn func(mcts.Stat) T) {
+	FillHist(root, &hist, valueFn)
+}
+
+// FillHist inserts the value of every child under root into hist.
+func FillHist[T Number](root *mcts.Node, hist *Hist[T], valueFn func(mcts.Stat) T) {
 	for n := range NodeSeq(root) {
 		for e := range lazyq.Payloads(n.Queue) {
 			x := valueFn(e.Stat)
@@ -50,30 +82,186 @@ func Fill[T int64 | float32](root *mcts.Node, hist Hist[T], valueFn func(mcts.St
 	}
 }
 
-func (h Hist[T]) Insert(x T) {
-	i, _ := slices.BinarySearchFunc(h.Bins, HistBin[T]{Max: x}, func(a, b HistBin[T]) int {
-		if a == b {
-			return 0
+// binIndex returns the index of the bin containing x or len(h.Bins) if x overflows.
+func (h Hist[T]) binIndex(x T) int {
+	i, _ := slices.BinarySearchFunc(h.Bins, x, func(b HistBin[T], x T) int { return cmp.Compare(b.Max, x) })
+	return i
+}
+
+// FillPriority fills hist with the bandit priority of every child under root.
+//
+// See mcts.Node.Priority.
+func FillPriority(root *mcts.Node, hist *Hist[float64]) {
+	for n := range NodeSeq(root) {
+		for e := range lazyq.Payloads(n.Queue) {
+			hist.Insert(float64(n.Priority(e)))
 		}
-		if a.Max < b.Max {
-			return -1
+	}
+}
+
+// Insert adds x to the Hist. NaN values are ignored.
+// ±Inf values are counted in Infinite instead of Sum.
+func (h *Hist[T]) Insert(x T) {
+	if x != x {
+		return // NaN.
+	}
+	if i := h.binIndex(x); i < len(h.Bins) {
+		h.Bins[i].Count++
+	} else {
+		h.Overflow++
+	}
+	h.addSum(x, +1)
+}
+
+// addSum adds sign*x to Sum, or sign to Infinite if x is ±Inf.
+func (h *Hist[T]) addSum(x T, sign int64) {
+	if math.IsInf(float64(x), 0) {
+		h.Infinite = max(h.Infinite+sign, 0)
+		return
+	}
+	h.Sum += float64(sign) * float64(x)
+}
+
+// Remove removes x from the Hist if its bin is not empty. NaN values are ignored.
+func (h *Hist[T]) Remove(x T) {
+	if x != x {
+		return // NaN.
+	}
+	count := &h.Overflow
+	if i := h.binIndex(x); i < len(h.Bins) {
+		count = &h.Bins[i].Count
+	}
+	if *count > 0 {
+		*count--
+		h.addSum(x, -1)
+	}
+}
+
+// Merge adds the counts and sum of other into h.
+//
+// Merge returns an error if the bins of other do not match h.
+func (h *Hist[T]) Merge(other Hist[T]) error {
+	if len(h.Bins) != len(other.Bins) {
+		return fmt.Errorf("cannot merge histograms with %d and %d bins", len(h.Bins), len(other.Bins))
+	}
+	for i, b := range other.Bins {
+		if h.Bins[i].Max != b.Max {
+			return fmt.Errorf("cannot merge histograms with mismatched bin %d: %v != %v", i, h.Bins[i].Max, b.Max)
 		}
-		return +1
-	})
-	h.Bins[i].Count++
+	}
+	for i, b := range other.Bins {
+		h.Bins[i].Count += b.Count
+	}
+	h.Overflow += other.Overflow
+	h.Sum += other.Sum
+	h.Infinite += other.Infinite
+	return nil
 }
 
-func (h Hist[T]) Remove(x T) {
-	i, _ := slices.BinarySearchFunc(h.Bins, HistBin[T]{Max: x}, func(a, b HistBin[T]) int {
-		if a == b {
-			return 0
+// Total returns the number of values in the Hist including Overflow.
+func (h Hist[T]) Total() int64 {
+	n := h.Overflow
+	for _, b := range h.Bins {
+		n += b.Count
+	}
+	return n
+}
+
+// Mean returns the mean of finite values in the Hist or NaN if there are none.
+func (h Hist[T]) Mean() float64 {
+	n := h.Total() - h.Infinite
+	if n <= 0 {
+		return math.NaN()
+	}
+	return h.Sum / float64(n)
+}
+
+// Quantile returns an approximation of the q-quantile of values in the Hist for q in [0, 1].
+//
+// The value is interpolated linearly within the bin containing the quantile.
+// Bins with an infinite bound return their finite bound.
+// Quantiles falling in Overflow return the Max of the last bin.
+// Quantile returns NaN if the Hist is empty.
+func (h Hist[T]) Quantile(q float64) float64 {
+	n := h.Total()
+	if n == 0 {
+		return math.NaN()
+	}
+	rank := q * float64(n)
+	var cum int64
+	for i, b := range h.Bins {
+		if b.Count == 0 || float64(cum+b.Count) < rank {
+			cum += b.Count
+			continue
+		}
+		hi := float64(b.Max)
+		lo := hi
+		if i > 0 {
+			lo = float64(h.Bins[i-1].Max)
+		}
+		switch {
+		case math.IsInf(hi, 0) || hi == math.MaxFloat64 || hi == -math.MaxFloat64:
+			return lo
+		case math.IsInf(lo, 0) || lo == -math.MaxFloat64:
+			return hi
+		}
+		frac := (rank - float64(cum)) / float64(b.Count)
+		return lo + frac*(hi-lo)
+	}
+	if len(h.Bins) == 0 {
+		return math.NaN()
+	}
+	return float64(h.Bins[len(h.Bins)-1].Max)
+}
+
+// P50 returns the approximate median. See Quantile.
+func (h Hist[T]) P50() float64 { return h.Quantile(0.5) }
+
+// P90 returns the approximate 90th percentile. See Quantile.
+func (h Hist[T]) P90() float64 { return h.Quantile(0.9) }
+
+// P99 returns the approximate 99th percentile. See Quantile.
+func (h Hist[T]) P99() float64 { return h.Quantile(0.99) }
+
+func formatMax[T Number](x T) string {
+	return strconv.FormatFloat(float64(x), 'g', 6, 64)
+}
+
+// WriteBars writes the Hist to w as an ASCII bar chart with bars at most width characters wide.
+func (h Hist[T]) WriteBars(w io.Writer, width int) error {
+	maxCount := h.Overflow
+	for _, b := range h.Bins {
+		maxCount = max(maxCount, b.Count)
+	}
+	bar := func(count int64) string {
+		if maxCount == 0 {
+			return ""
 		}
-		if a.Max < b.Max {
-			return -1
+		return strings.Repeat("#", int(float64(width)*float64(count)/float64(maxCount)))
+	}
+	for _, b := range h.Bins {
+		if _, err := fmt.Fprintf(w, "<= %12s %10d %s\n", formatMax(b.Max), b.Count, bar(b.Count)); err != nil {
+			return err
 		}
-		return +1
-	})
-	if h.Bins[i].Count > 0 {
-		h.Bins[i].Count--
 	}
+	if h.Overflow > 0 {
+		if _, err := fmt.Fprintf(w, "%15s %10d %s\n", "overflow", h.Overflow, bar(h.Overflow)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes the Hist to w as CSV with a header and a row per bin.
+func (h Hist[T]) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"max", "count"})
+	for _, b := range h.Bins {
+		cw.Write([]string{strconv.FormatFloat(float64(b.Max), 'g', -1, 64), strconv.FormatInt(b.Count, 10)})
+	}
+	if h.Overflow > 0 {
+		cw.Write([]string{"overflow", strconv.FormatInt(h.Overflow, 10)})
+	}
+	cw.Flush()
+	return cw.Error()
 }
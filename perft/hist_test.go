@@ -0,0 +1,216 @@
+package perft
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/ajzaff/mcts"
+)
+
+// linearBin is the reference bin contract: the first bin with x <= Max or len(bins) for overflow.
+func linearBin[T Number](bins []HistBin[T], x T) int {
+	for i, b := range bins {
+		if x <= b.Max {
+			return i
+		}
+	}
+	return len(bins)
+}
+
+func checkHist[T Number](t *testing.T, got Hist[T], want []int64) {
+	t.Helper()
+	for i, b := range got.Bins {
+		if b.Count != want[i] {
+			t.Fatalf("Hist: bin %d (Max=%v) got count %d, want %d", i, b.Max, b.Count, want[i])
+		}
+	}
+	if got.Overflow != want[len(got.Bins)] {
+		t.Fatalf("Hist: got overflow %d, want %d", got.Overflow, want[len(got.Bins)])
+	}
+}
+
+func TestHistInsertRemoveFloat64(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	// Bins do not end in +Inf so values may overflow.
+	bins := []float64{-1, 0, 0.5, 1, 2, 8}
+
+	h := MakeHist(bins)
+	want := make([]int64, len(bins)+1)
+	var values []float64
+	for range 10_000 {
+		var x float64
+		if r.IntN(2) == 0 {
+			x = bins[r.IntN(len(bins))] // Exactly on a bin edge.
+		} else {
+			x = r.NormFloat64() * 5
+		}
+		values = append(values, x)
+		h.Insert(x)
+		want[linearBin(h.Bins, x)]++
+		checkHist(t, h, want)
+	}
+	if got, want := h.Total(), int64(len(values)); got != want {
+		t.Errorf("Hist.Total(): got %d, want %d", got, want)
+	}
+	for _, x := range values {
+		h.Remove(x)
+		want[linearBin(h.Bins, x)]--
+		checkHist(t, h, want)
+	}
+	if h.Total() != 0 || math.Abs(h.Sum) > 1e-9 {
+		t.Errorf("Hist: got total %d and sum %v after removing all values, want 0 and 0", h.Total(), h.Sum)
+	}
+}
+
+func TestHistInsertInt64Edges(t *testing.T) {
+	h := MakeHist([]int64{0, 1, 2, 4})
+	for _, x := range []int64{-5, 0, 1, 2, 3, 4, 5} {
+		h.Insert(x)
+	}
+	checkHist(t, h, []int64{2, 1, 1, 2, 1})
+
+	// Removing from an empty bin is a no-op.
+	h.Remove(1)
+	h.Remove(1)
+	checkHist(t, h, []int64{2, 0, 1, 2, 1})
+}
+
+func TestHistInsertNaN(t *testing.T) {
+	h := MakeHist(DefaultScoreBins())
+	h.Insert(math.NaN())
+	h.Remove(math.NaN())
+	if h.Total() != 0 || h.Sum != 0 {
+		t.Errorf("Hist.Insert(NaN): got total %d and sum %v, want 0 and 0", h.Total(), h.Sum)
+	}
+}
+
+func TestHistMergeQuantile(t *testing.T) {
+	a, b := MakeHist([]float64{10, 20, 30, 40}), MakeHist([]float64{10, 20, 30, 40})
+	for x := range 20 {
+		a.Insert(float64(x) + 0.5)
+		b.Insert(float64(x) + 20.5)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Hist.Merge(): got err = %v, want nil", err)
+	}
+	if got, want := a.Mean(), 20.0; got != want {
+		t.Errorf("Hist.Mean(): got %v, want %v", got, want)
+	}
+	if got, want := a.P50(), 20.0; got != want {
+		t.Errorf("Hist.P50(): got %v, want %v", got, want)
+	}
+	if got, want := a.P90(), 36.0; got != want {
+		t.Errorf("Hist.P90(): got %v, want %v", got, want)
+	}
+	if err := a.Merge(MakeHist([]float64{1})); err == nil {
+		t.Errorf("Hist.Merge(mismatched): got err = nil, want error")
+	}
+}
+
+func TestHistQuantileEdges(t *testing.T) {
+	h := MakeHist(DefaultRunBins())
+	if got := h.P50(); !math.IsNaN(got) {
+		t.Errorf("Hist.P50(empty): got %v, want NaN", got)
+	}
+	if got := h.Mean(); !math.IsNaN(got) {
+		t.Errorf("Hist.Mean(empty): got %v, want NaN", got)
+	}
+	h.Insert(1e6) // Falls in the +Inf bin.
+	if got, want := h.P99(), 2048.0; got != want {
+		t.Errorf("Hist.P99(): got %v, want finite bound %v", got, want)
+	}
+	if got, want := h.Mean(), 1e6; got != want {
+		t.Errorf("Hist.Mean(): got %v, want %v", got, want)
+	}
+}
+
+func barsHist() Hist[float64] {
+	h := MakeHist([]float64{1, 2, 4})
+	for _, x := range []float64{0.5, 1.5, 1.7, 3, 3, 3, 3, 9} {
+		h.Insert(x)
+	}
+	return h
+}
+
+func TestHistWriteBars(t *testing.T) {
+	var sb strings.Builder
+	if err := barsHist().WriteBars(&sb, 8); err != nil {
+		t.Fatalf("Hist.WriteBars(): got err = %v, want nil", err)
+	}
+	const want = "" +
+		"<=            1          1 ##\n" +
+		"<=            2          2 ####\n" +
+		"<=            4          4 ########\n" +
+		"       overflow          1 ##\n"
+	if got := sb.String(); got != want {
+		t.Errorf("Hist.WriteBars(): got\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestHistWriteCSV(t *testing.T) {
+	var sb strings.Builder
+	if err := barsHist().WriteCSV(&sb); err != nil {
+		t.Fatalf("Hist.WriteCSV(): got err = %v, want nil", err)
+	}
+	const want = "max,count\n1,1\n2,2\n4,4\noverflow,1\n"
+	if got := sb.String(); got != want {
+		t.Errorf("Hist.WriteCSV(): got\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestHistInfinite(t *testing.T) {
+	h := MakeHist(DefaultScoreBins())
+	for _, x := range []float64{math.Inf(-1), math.Inf(+1), 0.5, 0.1, math.Inf(-1)} {
+		h.Insert(x)
+	}
+	if got, want := h.Total(), int64(5); got != want {
+		t.Errorf("Hist.Total(): got %d, want %d", got, want)
+	}
+	if h.Infinite != 3 || h.Sum != 0.6 {
+		t.Errorf("Hist.Insert(±Inf): got Infinite = %d, Sum = %v, want 3, 0.6", h.Infinite, h.Sum)
+	}
+	if got, want := h.Mean(), 0.3; got != want {
+		t.Errorf("Hist.Mean(): got %v, want %v", got, want)
+	}
+
+	h.Remove(math.Inf(-1))
+	h.Remove(math.Inf(+1))
+	if h.Infinite != 1 || h.Sum != 0.6 {
+		t.Errorf("Hist.Remove(±Inf): got Infinite = %d, Sum = %v, want 1, 0.6", h.Infinite, h.Sum)
+	}
+
+	inf := MakeHist(DefaultScoreBins())
+	inf.Insert(math.Inf(+1))
+	if got := inf.Mean(); !math.IsNaN(got) {
+		t.Errorf("Hist.Mean(+Inf only): got %v, want NaN", got)
+	}
+	if err := h.Merge(inf); err != nil || h.Infinite != 2 {
+		t.Errorf("Hist.Merge(): got err = %v, Infinite = %d, want nil, 2", err, h.Infinite)
+	}
+}
+
+func TestFillPriorityMean(t *testing.T) {
+	// Unopened children have +Inf priority.
+	root := mcts.Search(func(c *mcts.Context) {
+		c.Expand("a", "b", "c")
+		c.SetResultValue(1)
+	}, mcts.MaxIters(10)).Root
+
+	h := MakeHist(DefaultPriorityBins())
+	FillPriority(root, &h)
+	if h.Infinite == 0 {
+		t.Fatalf("FillPriority(): got no infinite priorities, want unopened children")
+	}
+	if got := h.Mean(); math.IsInf(got, 0) || math.IsNaN(got) {
+		t.Errorf("FillPriority(): got Mean() = %v, want finite", got)
+	}
+
+	// Fill takes the Hist by value and still fills the shared bins.
+	runs := MakeHist(DefaultRunBins())
+	Fill(root, runs, func(s mcts.Stat) float64 { return float64(s.Runs) })
+	if got, want := runs.Total(), h.Total(); got != want {
+		t.Errorf("Fill(): got %d values in bins, want %d", got, want)
+	}
+}
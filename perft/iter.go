@@ -1,24 +1,193 @@
 package perft
 
 import (
+	"container/heap"
 	"iter"
+	"slices"
 
 	"github.com/ajzaff/lazyq"
 	"github.com/ajzaff/mcts"
 )
 
+// visitNodes calls visitFn on each node under root recursively in queue order.
+//
+// The subtree of a node is skipped when visitFn returns false for it.
 func visitNodes(root *mcts.Node, depth int, visitFn func(n *mcts.Node, depth int) bool) {
-	if root == nil || !visitFn(root, depth) {
+	walkNodes(root, depth, func(n *mcts.Node, depth int) (bool, bool) { return visitFn(n, depth), true })
+}
+
+type frame struct {
+	n     *mcts.Node
+	depth int
+	// post marks frames revisited after their children in post-order.
+	post bool
+}
+
+// pushChildren pushes the opened children of n onto stack in reverse queue order
+// so they are popped in queue order.
+func pushChildren(stack []frame, n *mcts.Node, depth int) []frame {
+	i := len(stack)
+	for e := range lazyq.Payloads(n.Queue) {
+		if e.Node != nil {
+			stack = append(stack, frame{n: e.Node, depth: depth})
+		}
+	}
+	slices.Reverse(stack[i:])
+	return stack
+}
+
+// walkNodes calls walkFn on each node under root in depth-first pre-order and queue order.
+//
+// walkFn returns whether to descend into the node and whether to continue the walk.
+// walkNodes uses an explicit stack and is safe for very deep trees.
+func walkNodes(root *mcts.Node, depth int, walkFn func(n *mcts.Node, depth int) (descend, ok bool)) {
+	if root == nil {
 		return
 	}
-	for e := range lazyq.Payloads(root.Queue) {
-		visitNodes(e.Node, depth+1, visitFn)
+	stack := []frame{{n: root, depth: depth}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		descend, ok := walkFn(f.n, f.depth)
+		if !ok {
+			return
+		}
+		if descend {
+			stack = pushChildren(stack, f.n, f.depth+1)
+		}
 	}
 }
 
-// NodeSeq returns an iterator over all nodes under root recursively in descending priority order.
+// NodeSeq returns an iterator over all nodes under root in depth-first pre-order.
 func NodeSeq(root *mcts.Node) iter.Seq[*mcts.Node] {
 	return func(yield func(*mcts.Node) bool) {
-		visitNodes(root, 0, func(n *mcts.Node, _ int) bool { return yield(n) })
+		walkNodes(root, 0, func(n *mcts.Node, _ int) (bool, bool) { return true, yield(n) })
 	}
 }
+
+// DepthSeq returns an iterator over all nodes under root and their depth below root in depth-first pre-order.
+func DepthSeq(root *mcts.Node) iter.Seq2[int, *mcts.Node] { return PrunedSeq(root, nil) }
+
+// PrunedSeq returns an iterator over nodes under root and their depth below root in depth-first pre-order.
+//
+// Nodes for which keep returns false are skipped along with their subtree.
+// A nil keep keeps all nodes.
+func PrunedSeq(root *mcts.Node, keep func(n *mcts.Node, depth int) bool) iter.Seq2[int, *mcts.Node] {
+	return func(yield func(int, *mcts.Node) bool) {
+		walkNodes(root, 0, func(n *mcts.Node, depth int) (bool, bool) {
+			if keep != nil && !keep(n, depth) {
+				return false, true
+			}
+			return true, yield(depth, n)
+		})
+	}
+}
+
+// MaxDepth returns a keep function for PrunedSeq which keeps nodes up to depth d.
+func MaxDepth(d int) func(*mcts.Node, int) bool {
+	return func(_ *mcts.Node, depth int) bool { return depth <= d }
+}
+
+// PostOrder returns an iterator over all nodes under root and their depth below root in depth-first post-order.
+//
+// Children are yielded before their parent.
+func PostOrder(root *mcts.Node) iter.Seq2[int, *mcts.Node] {
+	return func(yield func(int, *mcts.Node) bool) {
+		if root == nil {
+			return
+		}
+		stack := []frame{{n: root}}
+		for len(stack) > 0 {
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if f.post {
+				if !yield(f.depth, f.n) {
+					return
+				}
+				continue
+			}
+			stack = append(stack, frame{n: f.n, depth: f.depth, post: true})
+			stack = pushChildren(stack, f.n, f.depth+1)
+		}
+	}
+}
+
+// BreadthFirst returns an iterator over all nodes under root and their depth below root in breadth-first order.
+//
+// All nodes at a depth are yielded before nodes at the next depth.
+func BreadthFirst(root *mcts.Node) iter.Seq2[int, *mcts.Node] {
+	return func(yield func(int, *mcts.Node) bool) {
+		if root == nil {
+			return
+		}
+		queue := []frame{{n: root}}
+		for len(queue) > 0 {
+			f := queue[0]
+			queue = queue[1:]
+			if !yield(f.depth, f.n) {
+				return
+			}
+			for e := range lazyq.Payloads(f.n.Queue) {
+				if e.Node != nil {
+					queue = append(queue, frame{n: e.Node, depth: f.depth + 1})
+				}
+			}
+		}
+	}
+}
+
+// ByRuns orders children by runs for BestFirst.
+func ByRuns(s mcts.Stat) float32 { return s.Runs }
+
+// ByScore orders children by score for BestFirst. Children without runs come last.
+func ByScore(s mcts.Stat) float32 { return s.Score() }
+
+// BestFirst returns an iterator over all children under root and their depth below root
+// in descending order of priority among the children discovered so far.
+//
+// The children of a child are discovered when it is yielded. Unopened children are yielded but have no children.
+func BestFirst(root *mcts.Node, priority func(mcts.Stat) float32) iter.Seq2[int, mcts.Child] {
+	return func(yield func(int, mcts.Child) bool) {
+		if root == nil {
+			return
+		}
+		h := &childHeap{priority: priority}
+		h.pushChildren(root, 1)
+		for h.Len() > 0 {
+			e := heap.Pop(h).(childEntry)
+			if !yield(e.depth, e.Child) {
+				return
+			}
+			if e.Node != nil {
+				h.pushChildren(e.Node, e.depth+1)
+			}
+		}
+	}
+}
+
+type childEntry struct {
+	mcts.Child
+	depth    int
+	priority float32
+}
+
+type childHeap struct {
+	entries  []childEntry
+	priority func(mcts.Stat) float32
+}
+
+func (h *childHeap) pushChildren(n *mcts.Node, depth int) {
+	for e := range lazyq.Payloads(n.Queue) {
+		heap.Push(h, childEntry{Child: e, depth: depth, priority: h.priority(e.Stat)})
+	}
+}
+
+func (h childHeap) Len() int           { return len(h.entries) }
+func (h childHeap) Less(i, j int) bool { return h.entries[i].priority > h.entries[j].priority }
+func (h childHeap) Swap(i, j int)      { h.entries[i], h.entries[j] = h.entries[j], h.entries[i] }
+func (h *childHeap) Push(x any)        { h.entries = append(h.entries, x.(childEntry)) }
+func (h *childHeap) Pop() any {
+	e := h.entries[len(h.entries)-1]
+	h.entries = h.entries[:len(h.entries)-1]
+	return e
+}
@@ -0,0 +1,139 @@
+package perft
+
+import (
+	"iter"
+	"runtime/debug"
+	"testing"
+
+	"github.com/ajzaff/lazyq"
+	"github.com/ajzaff/mcts"
+)
+
+func testTree() *mcts.Node {
+	return mcts.Search(func(c *mcts.Context) {
+		if c.Len() < 3 {
+			c.Expand("a", "b")
+		}
+		c.SetResultValue(1)
+	}, mcts.MaxIters(100)).Root
+}
+
+func TestDepthSeqEarlyStop(t *testing.T) {
+	root := testTree()
+	var n int
+	for range DepthSeq(root) {
+		if n++; n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("DepthSeq(): got %d nodes before break, want 3", n)
+	}
+}
+
+func TestPrunedSeqMaxDepth(t *testing.T) {
+	root := testTree()
+	count := ReduceSeq(PrunedSeq(root, MaxDepth(1)), 0, func(depth int, _ *mcts.Node, n int) (int, bool) {
+		if depth > 1 {
+			t.Errorf("PrunedSeq(MaxDepth(1)): got node at depth %d", depth)
+		}
+		return n + 1, true
+	})
+	if count != 3 {
+		t.Errorf("PrunedSeq(MaxDepth(1)): got %d nodes, want 3", count)
+	}
+
+	// Reduce into a map accumulator and stop after the first leaf.
+	depths := ReduceSeq(DepthSeq(root), map[int]int{}, func(depth int, n *mcts.Node, m map[int]int) (map[int]int, bool) {
+		m[depth]++
+		return m, n.Queue.Len() > 0
+	})
+	if len(depths) != 4 {
+		t.Errorf("ReduceSeq(): got depths %v, want 4 depths before the first leaf", depths)
+	}
+}
+
+// chain returns a tree of a single line of the given depth.
+//
+// Children are attached directly to their parent's queue so the chain is built in O(depth).
+func chain(depth int) *mcts.Node {
+	root := &mcts.Node{}
+	n := root
+	for range depth {
+		n.NewChild("a", 1)
+		c := lazyq.At(n.Queue, 0)
+		c.Runs, c.Value = 1, 1
+		c.Node = &mcts.Node{Parent: n, Action: "a"}
+		lazyq.ReplacePayload(n.Queue, 0, c)
+		n = c.Node
+	}
+	return root
+}
+
+func TestTraversalsDeepTree(t *testing.T) {
+	const depth = 1_000_000
+	root := chain(depth)
+
+	// A recursive walk needs at least tens of bytes of stack per level and would exceed this limit.
+	defer debug.SetMaxStack(debug.SetMaxStack(8 << 20))
+	for name, seq := range map[string]iter.Seq2[int, *mcts.Node]{
+		"DepthSeq":     DepthSeq(root),
+		"PostOrder":    PostOrder(root),
+		"BreadthFirst": BreadthFirst(root),
+	} {
+		var n, maxDepth int
+		for d := range seq {
+			n++
+			maxDepth = max(maxDepth, d)
+		}
+		if n != depth+1 || maxDepth != depth {
+			t.Errorf("%s(): got %d nodes with max depth %d, want %d and %d", name, n, maxDepth, depth+1, depth)
+		}
+	}
+}
+
+func TestTraversalOrders(t *testing.T) {
+	root := testTree()
+
+	var nodes int
+	for range DepthSeq(root) {
+		nodes++
+	}
+
+	var last *mcts.Node
+	var post int
+	for _, n := range PostOrder(root) {
+		post++
+		last = n
+	}
+	if post != nodes || last != root {
+		t.Errorf("PostOrder(): got %d nodes ending with root = %v, want %d ending with root", post, last == root, nodes)
+	}
+
+	prev, bfs := 0, 0
+	for d := range BreadthFirst(root) {
+		if d < prev {
+			t.Errorf("BreadthFirst(): got depth %d after %d, want nondecreasing", d, prev)
+		}
+		prev = d
+		bfs++
+	}
+	if bfs != nodes {
+		t.Errorf("BreadthFirst(): got %d nodes, want %d", bfs, nodes)
+	}
+
+	var rootMax float32
+	for c := range lazyq.Payloads(root.Queue) {
+		rootMax = max(rootMax, c.Runs)
+	}
+	var children int
+	for d, c := range BestFirst(root, ByRuns) {
+		if children == 0 && (d != 1 || c.Runs != rootMax) {
+			t.Errorf("BestFirst(ByRuns): got first child at depth %d with %v runs, want depth 1 with %v runs", d, c.Runs, rootMax)
+		}
+		children++
+	}
+	if want := nodes - 1; children < want {
+		t.Errorf("BestFirst(): got %d children, want at least %d opened children", children, want)
+	}
+}
@@ -0,0 +1,80 @@
+package perft
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+
+	"github.com/ajzaff/lazyq"
+	"github.com/ajzaff/mcts"
+)
+
+// DepthStats summarizes the nodes at a single depth of the tree.
+type DepthStats struct {
+	Depth          int64
+	NodeCount      int64
+	OpenedCount    int64 // Children of nodes at this depth which have been opened.
+	UnopenedCount  int64 // Children of nodes at this depth which have not been opened.
+	ExhaustedCount int64
+	Runs           float32 // Sum of the runs of children of nodes at this depth.
+}
+
+// MeanBranching returns the mean number of children per node.
+func (s DepthStats) MeanBranching() float64 {
+	if s.NodeCount == 0 {
+		return 0
+	}
+	return float64(s.OpenedCount+s.UnopenedCount) / float64(s.NodeCount)
+}
+
+// EffectiveBranching returns the effective branching factor N^(1/d) where N is NodeCount at depth d.
+//
+// It is the branching factor of a uniform tree with as many nodes at this depth.
+// EffectiveBranching returns 0 at depth 0.
+func (s DepthStats) EffectiveBranching() float64 {
+	if s.Depth == 0 || s.NodeCount == 0 {
+		return 0
+	}
+	return math.Pow(float64(s.NodeCount), 1/float64(s.Depth))
+}
+
+// Profile contains DepthStats for each depth of the tree starting from root at depth 0.
+type Profile []DepthStats
+
+// DepthProfile computes the per-depth Profile of the tree under root.
+func DepthProfile(root *mcts.Node) Profile {
+	var p Profile
+	visitNodes(root, 0, func(n *mcts.Node, depth int) bool {
+		for len(p) <= depth {
+			p = append(p, DepthStats{Depth: int64(len(p))})
+		}
+		s := &p[depth]
+		s.NodeCount++
+		if n.Exhausted() {
+			s.ExhaustedCount++
+		}
+		for c := range lazyq.Payloads(n.Queue) {
+			if c.Node != nil {
+				s.OpenedCount++
+			} else {
+				s.UnopenedCount++
+			}
+			s.Runs += c.Runs
+		}
+		return true
+	})
+	return p
+}
+
+// WriteTable writes the Profile to w as a text table.
+func (p Profile) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "depth\tnodes\topened\tunopened\texhausted\truns\tmean_bf\teff_bf\t")
+	for _, s := range p {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t%g\t%.2f\t%.2f\t\n",
+			s.Depth, s.NodeCount, s.OpenedCount, s.UnopenedCount, s.ExhaustedCount, s.Runs,
+			s.MeanBranching(), s.EffectiveBranching())
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,58 @@
+package perft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ajzaff/mcts"
+)
+
+func TestDepthProfile(t *testing.T) {
+	// After 4 iterations the root and both of its children are expanded
+	// and one grandchild is opened as a terminal leaf.
+	root := mcts.Search(func(c *mcts.Context) {
+		if c.Len() < 2 {
+			c.Expand("a", "b")
+		} else {
+			c.Expand()
+		}
+		c.SetResultValue(1)
+	}, mcts.MaxIters(4), mcts.ExpandShuffle(false)).Root
+
+	p := DepthProfile(root)
+	want := Profile{
+		{Depth: 0, NodeCount: 1, OpenedCount: 2, UnopenedCount: 0, ExhaustedCount: 1, Runs: 3},
+		{Depth: 1, NodeCount: 2, OpenedCount: 1, UnopenedCount: 3, ExhaustedCount: 2, Runs: 1},
+		{Depth: 2, NodeCount: 1, OpenedCount: 0, UnopenedCount: 0, ExhaustedCount: 1, Runs: 0},
+	}
+	if len(p) != len(want) {
+		t.Fatalf("DepthProfile(): got %d depths, want %d", len(p), len(want))
+	}
+	for i := range want {
+		if p[i] != want[i] {
+			t.Errorf("DepthProfile(): depth %d got %+v, want %+v", i, p[i], want[i])
+		}
+	}
+
+	for i, want := range []struct{ mean, eff float64 }{{2, 0}, {2, 2}, {0, 1}} {
+		if got := p[i].MeanBranching(); got != want.mean {
+			t.Errorf("MeanBranching(): depth %d got %v, want %v", i, got, want.mean)
+		}
+		if got := p[i].EffectiveBranching(); got != want.eff {
+			t.Errorf("EffectiveBranching(): depth %d got %v, want %v", i, got, want.eff)
+		}
+	}
+
+	var sb strings.Builder
+	if err := p.WriteTable(&sb); err != nil {
+		t.Fatalf("WriteTable(): got err = %v, want nil", err)
+	}
+	const wantTable = "" +
+		"  depth  nodes  opened  unopened  exhausted  runs  mean_bf  eff_bf\n" +
+		"      0      1       2         0          1     3     2.00    0.00\n" +
+		"      1      2       1         3          2     1     2.00    2.00\n" +
+		"      2      1       0         0          1     0     0.00    1.00\n"
+	if got := sb.String(); got != wantTable {
+		t.Errorf("WriteTable(): got\n%q\nwant\n%q", got, wantTable)
+	}
+}
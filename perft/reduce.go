@@ -1,6 +1,7 @@
 package perft
 
 import (
+	"iter"
 	"math"
 
 	"github.com/ajzaff/lazyq"
@@ -8,7 +9,7 @@ import (
 )
 
 // Reduce a series of measures in bulk on Nodes.
-func Reduce[T int64 | float32 | []int64 | []float32 | Hist[int64] | Hist[float32]](root *mcts.Node, v0 T, reduceFn func(*mcts.Node, T) T) T {
+func Reduce[T any](root *mcts.Node, v0 T, reduceFn func(*mcts.Node, T) T) T {
 	v := v0
 	for n := range NodeSeq(root) {
 		v = reduceFn(n, v)
@@ -17,7 +18,7 @@ func Reduce[T int64 | float32 | []int64 | []float32 | Hist[int64] | Hist[float32
 }
 
 // ReduceChild reduces a series of node children.
-func ReduceChild[T int64 | float32 | []int64 | []float32 | Hist[int64] | Hist[float32]](root *mcts.Node, v0 T, reduceFn func(*mcts.Node, mcts.Child, T) T) T {
+func ReduceChild[T any](root *mcts.Node, v0 T, reduceFn func(*mcts.Node, mcts.Child, T) T) T {
 	v := v0
 	for n := range NodeSeq(root) {
 		for s := range lazyq.Payloads(n.Queue) {
@@ -27,6 +28,23 @@ func ReduceChild[T int64 | float32 | []int64 | []float32 | Hist[int64] | Hist[fl
 	return v
 }
 
+// ReduceSeq reduces the nodes and depths of seq such as from DepthSeq or PrunedSeq.
+//
+// The reduction stops early when reduceFn returns false.
+func ReduceSeq[T any](seq iter.Seq2[int, *mcts.Node], v0 T, reduceFn func(depth int, n *mcts.Node, v T) (T, bool)) T {
+	v := v0
+	for depth, n := range seq {
+		var ok bool
+		if v, ok = reduceFn(depth, n, v); !ok {
+			break
+		}
+	}
+	return v
+}
+
+// Min returns the parent of the child minimizing valueFn.
+//
+// Deprecated: Min returns the parent node rather than the winning child. Use ArgMin.
 func Min(root *mcts.Node, valueFn func(*mcts.Node, mcts.Stat) float32) *mcts.Node {
 	var (
 		v0      = float32(math.Inf(+1))
@@ -43,6 +61,9 @@ func Min(root *mcts.Node, valueFn func(*mcts.Node, mcts.Stat) float32) *mcts.Nod
 	return minNode
 }
 
+// Max returns the parent of the child maximizing valueFn.
+//
+// Deprecated: Max returns the parent node rather than the winning child. Use ArgMax.
 func Max(root *mcts.Node, valueFn func(*mcts.Node, mcts.Stat) float32) *mcts.Node {
 	var (
 		v0      = float32(math.Inf(-1))
@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
 	"plugin"
 
@@ -16,13 +17,144 @@ func LoadSearchFunc(path string) (mcts.Func, error) {
 	if err != nil {
 		return nil, err
 	}
+	return lookupSearch(x)
+}
+
+// lookupSearch looks up the Search symbol.
+//
+// Lookup returns a func value for function declarations and a pointer for variables.
+// Search may be declared as a function with signature func(*mcts.Context) or
+// as a variable of type mcts.Func or func(*mcts.Context).
+func lookupSearch(x *plugin.Plugin) (mcts.Func, error) {
 	sym, err := x.Lookup("Search")
 	if err != nil {
 		return nil, err
 	}
-	fn, ok := sym.(mcts.Func)
-	if !ok {
-		return nil, fmt.Errorf("expected Search to be %T, but found %T", mcts.Func(nil), fn)
+	var fn mcts.Func
+	switch v := sym.(type) {
+	case func(*mcts.Context):
+		fn = v
+	case mcts.Func:
+		fn = v
+	case *mcts.Func:
+		fn = *v
+	case *func(*mcts.Context):
+		fn = *v
+	default:
+		return nil, fmt.Errorf("expected Search to be %T or %T, but found %T", (func(*mcts.Context))(nil), (*mcts.Func)(nil), sym)
+	}
+	if fn == nil {
+		return nil, errors.New("expected Search to be non-nil")
 	}
 	return fn, nil
 }
+
+// Plugin exposes the symbols provided by a search plugin.
+//
+// Only Search is required. The plugin may optionally export:
+//
+//	var Name string           // or func Name() string
+//	var Version string        // or func Version() string
+//	func Options() []mcts.Option
+//	func Init(config map[string]string) error
+//	func Root() *mcts.Node
+type Plugin struct {
+	Name    string
+	Version string
+	Search  mcts.Func
+
+	options func() []mcts.Option
+	init    func(map[string]string) error
+	root    func() *mcts.Node
+}
+
+// Load loads the plugin at path and looks up the symbols it provides.
+//
+// Load returns a descriptive error when a symbol exists but has an unexpected type.
+func Load(path string) (*Plugin, error) {
+	x, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	p := new(Plugin)
+	if p.Search, err = lookupSearch(x); err != nil {
+		return nil, err
+	}
+	if p.Name, err = lookupString(x, "Name"); err != nil {
+		return nil, err
+	}
+	if p.Version, err = lookupString(x, "Version"); err != nil {
+		return nil, err
+	}
+	if p.options, err = lookupFunc[func() []mcts.Option](x, "Options"); err != nil {
+		return nil, err
+	}
+	if p.init, err = lookupFunc[func(map[string]string) error](x, "Init"); err != nil {
+		return nil, err
+	}
+	if p.root, err = lookupFunc[func() *mcts.Node](x, "Root"); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// lookupString looks up an optional string variable or function symbol.
+func lookupString(x *plugin.Plugin, name string) (string, error) {
+	sym, err := x.Lookup(name)
+	if err != nil {
+		return "", nil // Not provided.
+	}
+	switch v := sym.(type) {
+	case *string:
+		return *v, nil
+	case func() string:
+		return v(), nil
+	default:
+		return "", fmt.Errorf("expected %s to be %T or %T, but found %T", name, "", (func() string)(nil), sym)
+	}
+}
+
+// lookupFunc looks up an optional function symbol of type F.
+//
+// lookupFunc returns the zero F when no such symbol exists.
+func lookupFunc[F any](x *plugin.Plugin, name string) (fn F, err error) {
+	sym, err := x.Lookup(name)
+	if err != nil {
+		return fn, nil // Not provided.
+	}
+	switch v := sym.(type) {
+	case F:
+		return v, nil
+	case *F:
+		return *v, nil
+	default:
+		return fn, fmt.Errorf("expected %s to be %T, but found %T", name, fn, sym)
+	}
+}
+
+// Init initializes the plugin with the configuration key/values.
+//
+// Init does nothing if the plugin does not export Init.
+func (p *Plugin) Init(config map[string]string) error {
+	if p.init == nil {
+		return nil
+	}
+	return p.init(config)
+}
+
+// Options returns the search options preferred by the plugin.
+//
+// If the plugin exports Root, the options continue the search from the returned root.
+// Options passed to mcts.Search after these override them.
+func (p *Plugin) Options() []mcts.Option {
+	var opts []mcts.Option
+	if p.options != nil {
+		opts = append(opts, p.options()...)
+	}
+	if p.root != nil {
+		if root := p.root(); root != nil {
+			opts = append(opts, mcts.UseContinuation(root))
+		}
+	}
+	return opts
+}
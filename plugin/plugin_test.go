@@ -0,0 +1,109 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ajzaff/mcts"
+)
+
+var (
+	pluginDir      string
+	pluginBuildErr error
+	// pluginBuildFlags are passed to go build. See race_test.go.
+	pluginBuildFlags = []string{"-buildmode=plugin"}
+)
+
+// TestMain builds the test plugins under testdata/plugins before running tests.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "mcts_plugin_test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	pluginDir = dir
+	for _, name := range []string{"func", "var", "funcvar", "manifest", "root", "badtype"} {
+		args := append([]string{"build"}, pluginBuildFlags...)
+		args = append(args, "-o", filepath.Join(dir, name+".so"), "./testdata/plugins/"+name)
+		cmd := exec.Command("go", args...)
+		if b, err := cmd.CombinedOutput(); err != nil {
+			pluginBuildErr = fmt.Errorf("building plugin %s: %v\n%s", name, err, b)
+			break
+		}
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func pluginPath(t *testing.T, name string) string {
+	t.Helper()
+	if pluginBuildErr != nil {
+		t.Skip(pluginBuildErr)
+	}
+	return filepath.Join(pluginDir, name+".so")
+}
+
+func TestLoadSearchFunc(t *testing.T) {
+	for _, name := range []string{"func", "var", "funcvar"} {
+		t.Run(name, func(t *testing.T) {
+			fn, err := LoadSearchFunc(pluginPath(t, name))
+			if err != nil {
+				t.Fatalf("LoadSearchFunc(%q): got err = %v, want nil", name, err)
+			}
+			results := mcts.Search(fn, mcts.MaxIters(10))
+			if got := results.Root.Queue.Len(); got != 2 {
+				t.Errorf("LoadSearchFunc(%q): got %d root children, want 2", name, got)
+			}
+		})
+	}
+}
+
+func TestLoadSearchFuncBadType(t *testing.T) {
+	_, err := LoadSearchFunc(pluginPath(t, "badtype"))
+	if err == nil || !strings.Contains(err.Error(), "func(int)") {
+		t.Errorf("LoadSearchFunc(badtype): got err = %v, want error naming func(int)", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	p, err := Load(pluginPath(t, "manifest"))
+	if err != nil {
+		t.Fatalf("Load(): got err = %v, want nil", err)
+	}
+	if p.Name != "manifest" || p.Version != "v1.2.3" {
+		t.Errorf("Load(): got Name, Version = %q, %q, want %q, %q", p.Name, p.Version, "manifest", "v1.2.3")
+	}
+	if err := p.Init(map[string]string{"value": "2"}); err != nil {
+		t.Fatalf("Init(): got err = %v, want nil", err)
+	}
+	results := mcts.Search(p.Search, p.Options()...)
+	if results.Iterations != 7 {
+		t.Errorf("Search(): got %d iterations, want 7 from plugin Options", results.Iterations)
+	}
+	if err := p.Init(map[string]string{"value": "x"}); err == nil {
+		t.Errorf("Init(bad value): got err = nil, want error")
+	}
+}
+
+func TestLoadRoot(t *testing.T) {
+	p, err := Load(pluginPath(t, "root"))
+	if err != nil {
+		t.Fatalf("Load(): got err = %v, want nil", err)
+	}
+	first := mcts.Search(p.Search, p.Options()...)
+	if first.Root.Action != "resume" {
+		t.Fatalf("Search(): got root action %q, want the plugin Root %q", first.Root.Action, "resume")
+	}
+	// Each search continues from the same root.
+	trials := first.Root.Trials
+	second := mcts.Search(p.Search, p.Options()...)
+	if second.Root != first.Root || second.Root.Trials <= trials {
+		t.Errorf("Search(): got root %p with %v trials, want continued root %p with more than %v trials",
+			second.Root, second.Root.Trials, first.Root, trials)
+	}
+}
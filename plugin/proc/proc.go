@@ -0,0 +1,190 @@
+// Package proc runs search functions in a child process over a line-delimited JSON protocol.
+//
+// The parent writes one line per batch containing a JSON array of Request values to the child's stdin.
+// The child replies with one line containing a JSON array of Response values in the same order on its stdout.
+// The child exits when its stdin is closed.
+//
+// Serve implements the child side of the protocol for search functions written in Go.
+package proc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+
+	"github.com/ajzaff/mcts"
+)
+
+// Request asks the child to run experiments on the node reached by Actions from the root.
+type Request struct {
+	ID      int64    `json:"id"`
+	Actions []string `json:"actions"`
+}
+
+// Response carries the result of a Request back to the parent.
+//
+// The fields map onto the methods of mcts.Context.
+type Response struct {
+	ID int64 `json:"id"`
+	// Expand adds actions and exhausts the node. See Context.Expand.
+	Expand []string `json:"expand,omitempty"`
+	// Append adds actions without exhausting the node. See Context.Append.
+	Append []string `json:"append,omitempty"`
+	// Priors are the priors for Append followed by Expand. See Context.Priors.
+	Priors []float32 `json:"priors,omitempty"`
+	Value  float32   `json:"value"`
+	// Count is the number of experiments behind Value.
+	// A zero Count is treated as a single run.
+	Count    float32 `json:"count,omitempty"`
+	Minimize bool    `json:"minimize,omitempty"`
+	// Stop stops the search with mcts.ErrStop.
+	Stop bool `json:"stop,omitempty"`
+	// Error stops the search with the given error.
+	Error string `json:"error,omitempty"`
+}
+
+// Process is a running child process implementing the protocol.
+//
+// Process is not safe for concurrent use.
+type Process struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	enc    *json.Encoder
+	dec    *json.Decoder
+	nextID int64
+	reqs   []Request
+}
+
+// Start starts cmd and returns the Process communicating with it.
+//
+// cmd must not have Stdin or Stdout set.
+func Start(cmd *exec.Cmd) (*Process, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &Process{
+		cmd:   cmd,
+		stdin: stdin,
+		enc:   json.NewEncoder(stdin),
+		dec:   json.NewDecoder(stdout),
+	}, nil
+}
+
+// Eval sends a batch of requests, one per action line, and returns the responses in order.
+func (p *Process) Eval(lines ...[]string) ([]Response, error) {
+	p.reqs = p.reqs[:0]
+	for _, line := range lines {
+		p.reqs = append(p.reqs, Request{ID: p.nextID, Actions: line})
+		p.nextID++
+	}
+	if err := p.enc.Encode(p.reqs); err != nil {
+		return nil, err
+	}
+	var res []Response
+	if err := p.dec.Decode(&res); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("reading responses: %w", err)
+	}
+	if len(res) != len(p.reqs) {
+		return nil, fmt.Errorf("got %d responses for %d requests", len(res), len(p.reqs))
+	}
+	for i, r := range res {
+		if r.ID != p.reqs[i].ID {
+			return nil, fmt.Errorf("got response id %d, want %d", r.ID, p.reqs[i].ID)
+		}
+	}
+	return res, nil
+}
+
+// Func returns an mcts.Func which evaluates each frontier node in the child process.
+//
+// Protocol errors stop the search with the error.
+func (p *Process) Func() mcts.Func {
+	return func(c *mcts.Context) {
+		res, err := p.Eval(slices.Collect(c.Actions()))
+		if err != nil {
+			c.StopErr(err)
+			return
+		}
+		Apply(c, res[0])
+	}
+}
+
+// Apply applies the Response r to the Context.
+func Apply(c *mcts.Context, r Response) {
+	if len(r.Append) > 0 {
+		c.Append(r.Append...)
+	}
+	if len(r.Expand) > 0 {
+		c.Expand(r.Expand...)
+	}
+	if len(r.Priors) > 0 {
+		c.Priors(r.Priors...)
+	}
+	if r.Minimize {
+		c.Minimize()
+	}
+	count := r.Count
+	if count == 0 {
+		count = 1
+	}
+	c.SetResult(r.Value, count)
+	switch {
+	case r.Error != "":
+		c.StopErr(errors.New(r.Error))
+	case r.Stop:
+		c.Stop()
+	}
+}
+
+// Close closes the child's stdin and waits for it to exit.
+func (p *Process) Close() error {
+	if err := p.stdin.Close(); err != nil {
+		return err
+	}
+	return p.cmd.Wait()
+}
+
+// Serve implements the child side of the protocol.
+//
+// Serve reads batches of requests from r, calls fn for each request
+// and writes the batch of responses to w. Serve returns nil when r is closed.
+func Serve(r io.Reader, w io.Writer, fn func(Request) Response) error {
+	dec := json.NewDecoder(r)
+	enc := json.NewEncoder(w)
+	var (
+		reqs []Request
+		res  []Response
+	)
+	for {
+		reqs = reqs[:0]
+		if err := dec.Decode(&reqs); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		res = res[:0]
+		for _, req := range reqs {
+			resp := fn(req)
+			resp.ID = req.ID
+			res = append(res, resp)
+		}
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+	}
+}
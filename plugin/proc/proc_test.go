@@ -0,0 +1,81 @@
+package proc
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/ajzaff/mcts"
+)
+
+const childEnv = "MCTS_PROC_TEST_CHILD"
+
+// TestMain runs the reference child when the test binary is started by Start.
+func TestMain(m *testing.M) {
+	if os.Getenv(childEnv) != "" {
+		err := Serve(os.Stdin, os.Stdout, func(req Request) Response {
+			var res Response
+			if len(req.Actions) < 3 {
+				res.Expand = []string{"a", "b"}
+				res.Priors = []float32{1, 1}
+			}
+			if len(req.Actions) > 0 && req.Actions[0] == "a" {
+				res.Value = 1
+			}
+			return res
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func startChild(t *testing.T) *Process {
+	t.Helper()
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), childEnv+"=1")
+	cmd.Stderr = os.Stderr
+	p, err := Start(cmd)
+	if err != nil {
+		t.Fatalf("Start(): got err = %v, want nil", err)
+	}
+	return p
+}
+
+func TestProcessSearch(t *testing.T) {
+	p := startChild(t)
+
+	results := mcts.Search(p.Func(), mcts.MaxIters(50))
+	if results.Err != nil {
+		t.Fatalf("Search(): got err = %v, want nil", results.Err)
+	}
+	if got := results.Root.Queue.Len(); got != 2 {
+		t.Errorf("Search(): got %d root children, want 2", got)
+	}
+	if err := p.Close(); err != nil {
+		t.Errorf("Close(): got err = %v, want nil", err)
+	}
+}
+
+func TestProcessEvalBatch(t *testing.T) {
+	p := startChild(t)
+	defer p.Close()
+
+	res, err := p.Eval(nil, []string{"a"}, []string{"b", "a", "b"})
+	if err != nil {
+		t.Fatalf("Eval(): got err = %v, want nil", err)
+	}
+	if len(res) != 3 {
+		t.Fatalf("Eval(): got %d responses, want 3", len(res))
+	}
+	if res[1].Value != 1 || res[2].Value != 0 {
+		t.Errorf("Eval(): got values (%v, %v), want (1, 0)", res[1].Value, res[2].Value)
+	}
+	if len(res[2].Expand) != 0 {
+		t.Errorf("Eval(): got expand %v at depth 3, want none", res[2].Expand)
+	}
+}
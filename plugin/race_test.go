@@ -0,0 +1,8 @@
+//go:build race
+
+package plugin
+
+func init() {
+	// Plugins must be built with the same flags as the test binary that loads them.
+	pluginBuildFlags = append(pluginBuildFlags, "-race")
+}
@@ -0,0 +1,4 @@
+// Command badtype is a test plugin declaring Search with the wrong type.
+package main
+
+func Search(n int) {}
@@ -0,0 +1,9 @@
+// Command func is a test plugin declaring Search as a function.
+package main
+
+import "github.com/ajzaff/mcts"
+
+func Search(c *mcts.Context) {
+	c.Expand("a", "b")
+	c.SetResultValue(1)
+}
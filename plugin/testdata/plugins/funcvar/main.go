@@ -0,0 +1,9 @@
+// Command funcvar is a test plugin declaring Search as a plain func variable.
+package main
+
+import "github.com/ajzaff/mcts"
+
+var Search = func(c *mcts.Context) {
+	c.Expand("a", "b")
+	c.SetResultValue(1)
+}
@@ -0,0 +1,34 @@
+// Command manifest is a test plugin exporting the optional plugin symbols.
+package main
+
+import (
+	"strconv"
+
+	"github.com/ajzaff/mcts"
+)
+
+var Name = "manifest"
+
+func Version() string { return "v1.2.3" }
+
+var value float32 = 1
+
+func Init(config map[string]string) error {
+	if v, ok := config["value"]; ok {
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return err
+		}
+		value = float32(f)
+	}
+	return nil
+}
+
+func Options() []mcts.Option { return []mcts.Option{mcts.MaxIters(7)} }
+
+func Root() *mcts.Node { return nil }
+
+func Search(c *mcts.Context) {
+	c.Expand("a", "b")
+	c.SetResultValue(value)
+}
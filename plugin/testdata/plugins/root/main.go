@@ -0,0 +1,15 @@
+// Command root is a test plugin exporting a Root to continue the search from.
+package main
+
+import "github.com/ajzaff/mcts"
+
+var root = &mcts.Node{Action: "resume"}
+
+func Root() *mcts.Node { return root }
+
+func Options() []mcts.Option { return []mcts.Option{mcts.MaxIters(5)} }
+
+func Search(c *mcts.Context) {
+	c.Expand("a", "b")
+	c.SetResultValue(1)
+}
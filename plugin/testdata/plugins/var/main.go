@@ -0,0 +1,9 @@
+// Command var is a test plugin declaring Search as an mcts.Func variable.
+package main
+
+import "github.com/ajzaff/mcts"
+
+var Search mcts.Func = func(c *mcts.Context) {
+	c.Expand("a", "b")
+	c.SetResultValue(1)
+}
@@ -0,0 +1,45 @@
+// Command search is a test WebAssembly search function.
+//
+// Build with GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared.
+package main
+
+import "unsafe"
+
+//go:wasmimport mcts action_count
+func actionCount() int32
+
+//go:wasmimport mcts action_at
+func actionAt(i int32, ptr unsafe.Pointer, n int32) int32
+
+//go:wasmimport mcts expand
+func expand(ptr unsafe.Pointer, n int32)
+
+//go:wasmimport mcts prior
+func prior(p float32)
+
+//go:wasmimport mcts set_result
+func setResult(value, count float32)
+
+var buf [64]byte
+
+func expandString(s string) { expand(unsafe.Pointer(unsafe.StringData(s)), int32(len(s))) }
+
+//go:wasmexport search
+func search() {
+	n := actionCount()
+	if n < 3 {
+		expandString("a")
+		expandString("b")
+		prior(1)
+		prior(1)
+	}
+	var value float32
+	if n > 0 {
+		if m := actionAt(0, unsafe.Pointer(&buf[0]), int32(len(buf))); string(buf[:m]) == "a" {
+			value = 1
+		}
+	}
+	setResult(value, 1)
+}
+
+func main() {}
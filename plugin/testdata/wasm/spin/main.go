@@ -0,0 +1,15 @@
+// Command spin is a test WebAssembly search function which never returns.
+//
+// Build with GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared.
+package main
+
+var n int
+
+//go:wasmexport search
+func search() {
+	for {
+		n++
+	}
+}
+
+func main() {}
@@ -0,0 +1,142 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ajzaff/mcts"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// WasmSearch is a search function loaded from a WebAssembly module.
+//
+// The module must export a function named "search" with no parameters or results
+// which is called once per frontier node. Reactor modules exporting "_initialize" are initialized on load.
+// WASI is available to the module without file system access.
+//
+// The module may import the following functions from the "mcts" module:
+//
+//	action_count() i32                      // Context.Len.
+//	action_at(i i32, ptr i32, len i32) i32  // Copies Context.ActionAt(i) to memory and returns its length or -1.
+//	                                        // Nothing is copied when the action is longer than len.
+//	expand(ptr i32, len i32)                // Context.Expand with one action.
+//	append(ptr i32, len i32)                // Context.Append with one action.
+//	exhaust()                               // Context.Expand with no actions.
+//	prior(p f32)                            // Context.Priors with one prior.
+//	set_result(value f32, count f32)        // Context.SetResult.
+//	add_result(value f32, count f32)        // Context.AddResult.
+//	minimize()                              // Context.Minimize.
+//	stop()                                  // Context.Stop.
+//
+// WasmSearch is not safe for concurrent use.
+type WasmSearch struct {
+	// CallTimeout limits the duration of each call to the module.
+	// When exceeded, the module is closed and the search stops with the error.
+	// The default 0 sets no limit.
+	CallTimeout time.Duration
+
+	rt     wazero.Runtime
+	mod    api.Module
+	search api.Function
+	c      *mcts.Context // Context of the current call.
+}
+
+// LoadWasmSearchFunc loads and instantiates the WebAssembly module at path.
+//
+// Pass the Search method to mcts.Search and call Close when done.
+func LoadWasmSearchFunc(path string) (*WasmSearch, error) {
+	binary, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	w := &WasmSearch{rt: wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true))}
+	if err := w.init(ctx, binary); err != nil {
+		w.rt.Close(ctx)
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *WasmSearch) init(ctx context.Context, binary []byte) error {
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, w.rt); err != nil {
+		return err
+	}
+	if err := w.instantiateHost(ctx); err != nil {
+		return err
+	}
+	mod, err := w.rt.InstantiateWithConfig(ctx, binary, wazero.NewModuleConfig().WithStartFunctions("_initialize"))
+	if err != nil {
+		return err
+	}
+	w.mod = mod
+	if w.search = mod.ExportedFunction("search"); w.search == nil {
+		return errors.New(`expected wasm module to export "search"`)
+	}
+	if d := w.search.Definition(); len(d.ParamTypes()) != 0 || len(d.ResultTypes()) != 0 {
+		return fmt.Errorf(`expected "search" to have no params or results, but found %v -> %v`, d.ParamTypes(), d.ResultTypes())
+	}
+	return nil
+}
+
+func (w *WasmSearch) readAction(m api.Module, ptr, n uint32) string {
+	b, ok := m.Memory().Read(ptr, n)
+	if !ok {
+		panic(fmt.Errorf("action out of range of memory [%d, %d)", ptr, ptr+n))
+	}
+	return string(b)
+}
+
+func (w *WasmSearch) instantiateHost(ctx context.Context) error {
+	_, err := w.rt.NewHostModuleBuilder("mcts").
+		NewFunctionBuilder().WithFunc(func() int32 { return int32(w.c.Len()) }).Export("action_count").
+		NewFunctionBuilder().WithFunc(func(_ context.Context, m api.Module, i int32, ptr, n uint32) int32 {
+		if i < 0 || int(i) >= w.c.Len() {
+			return -1
+		}
+		a := w.c.ActionAt(int(i))
+		if len(a) <= int(n) && !m.Memory().WriteString(ptr, a) {
+			panic(fmt.Errorf("action buffer out of range of memory [%d, %d)", ptr, ptr+n))
+		}
+		return int32(len(a))
+	}).Export("action_at").
+		NewFunctionBuilder().WithFunc(func(_ context.Context, m api.Module, ptr, n uint32) {
+		w.c.Expand(w.readAction(m, ptr, n))
+	}).Export("expand").
+		NewFunctionBuilder().WithFunc(func(_ context.Context, m api.Module, ptr, n uint32) {
+		w.c.Append(w.readAction(m, ptr, n))
+	}).Export("append").
+		NewFunctionBuilder().WithFunc(func() { w.c.Expand() }).Export("exhaust").
+		NewFunctionBuilder().WithFunc(func(p float32) { w.c.Priors(p) }).Export("prior").
+		NewFunctionBuilder().WithFunc(func(v, n float32) { w.c.SetResult(v, n) }).Export("set_result").
+		NewFunctionBuilder().WithFunc(func(v, n float32) { w.c.AddResult(v, n) }).Export("add_result").
+		NewFunctionBuilder().WithFunc(func() { w.c.Minimize() }).Export("minimize").
+		NewFunctionBuilder().WithFunc(func() { w.c.Stop() }).Export("stop").
+		Instantiate(ctx)
+	return err
+}
+
+// Search calls the module's search function with c.
+//
+// Errors from the module, including exceeding CallTimeout, stop the search and are returned in Result.Err.
+func (w *WasmSearch) Search(c *mcts.Context) {
+	ctx := context.Background()
+	if w.CallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.CallTimeout)
+		defer cancel()
+	}
+	w.c = c
+	defer func() { w.c = nil }()
+	if _, err := w.search.Call(ctx); err != nil {
+		c.StopErr(fmt.Errorf("wasm search: %w", err))
+	}
+}
+
+// Close closes the module and releases its resources.
+func (w *WasmSearch) Close() error { return w.rt.Close(context.Background()) }
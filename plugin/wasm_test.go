@@ -0,0 +1,59 @@
+package plugin
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ajzaff/mcts"
+	"github.com/ajzaff/mcts/variation"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+func buildWasm(t *testing.T, pkg string) string {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), filepath.Base(pkg)+".wasm")
+	cmd := exec.Command("go", "build", "-buildmode=c-shared", "-o", out, pkg)
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if b, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("building %s: %v\n%s", pkg, err, b)
+	}
+	return out
+}
+
+func TestLoadWasmSearchFunc(t *testing.T) {
+	w, err := LoadWasmSearchFunc(buildWasm(t, "./testdata/wasm/search"))
+	if err != nil {
+		t.Fatalf("LoadWasmSearchFunc(): got err = %v, want nil", err)
+	}
+	defer w.Close()
+
+	results := mcts.Search(w.Search, mcts.MaxIters(100))
+	if results.Err != nil {
+		t.Fatalf("Search(): got err = %v, want nil", results.Err)
+	}
+	if a, b := variation.Stat(results.Root, "a"), variation.Stat(results.Root, "b"); a.Score() <= b.Score() {
+		t.Errorf("Search(): got score(a) = %v <= score(b) = %v, want a > b", a.Score(), b.Score())
+	}
+}
+
+func TestLoadWasmSearchFuncTimeout(t *testing.T) {
+	w, err := LoadWasmSearchFunc(buildWasm(t, "./testdata/wasm/spin"))
+	if err != nil {
+		t.Fatalf("LoadWasmSearchFunc(): got err = %v, want nil", err)
+	}
+	defer w.Close()
+	w.CallTimeout = 10 * time.Millisecond
+
+	results := mcts.Search(w.Search, mcts.MaxIters(10))
+	var exitErr *sys.ExitError
+	if !errors.As(results.Err, &exitErr) || exitErr.ExitCode() != sys.ExitCodeDeadlineExceeded {
+		t.Errorf("Search(): got err = %v, want deadline exceeded", results.Err)
+	}
+	if results.Iterations != 1 {
+		t.Errorf("Search(): got %d iterations, want 1", results.Iterations)
+	}
+}
@@ -0,0 +1,144 @@
+package mcts
+
+import (
+	"sync"
+
+	"github.com/ajzaff/lazyq"
+)
+
+// Ponderer runs a search continuously in the background.
+//
+// The tree is only accessed by the search goroutine while it runs.
+// Methods on Ponderer pause the search before accessing the tree, so they are safe for concurrent use.
+type Ponderer struct {
+	fn   Func
+	opts []Option
+
+	mu        sync.Mutex
+	root      *Node
+	result    Result
+	interrupt chan struct{} // closed to pause the search.
+	paused    chan struct{}
+	resume    chan bool // receives whether to restart the search.
+	idle      bool      // set when the search finished on its own.
+	stopped   bool
+	done      chan struct{}
+}
+
+// Ponder starts searching with fn and opts in the background and returns the Ponderer.
+//
+// Ponder searches from a new root unless UseContinuation is given.
+// Options which stop the search such as MaxIters leave the Ponderer idle until the next Play.
+func Ponder(fn Func, opts ...Option) *Ponderer {
+	p := &Ponderer{
+		fn:        fn,
+		opts:      opts,
+		interrupt: make(chan struct{}),
+		paused:    make(chan struct{}),
+		resume:    make(chan bool),
+		done:      make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *Ponderer) run() {
+	defer close(p.done)
+	restart := true
+	for {
+		interrupt := p.interrupt
+		if restart {
+			opts := p.opts[:len(p.opts):len(p.opts)]
+			if p.root != nil {
+				// Continue from the current root. The first search uses the root given by opts, if any.
+				opts = append(opts, UseContinuation(p.root))
+			}
+			res := Search(p.fn, append(opts, Done(interrupt))...)
+			p.root, p.result.Root = res.Root, res.Root
+			p.result.Iterations += res.Iterations
+			p.result.Duration += res.Duration
+			p.result.Err = res.Err
+			select {
+			case <-interrupt:
+				p.idle = false
+			default:
+				p.idle = true
+			}
+		}
+		// Wait for a request and hand over the tree.
+		<-interrupt
+		p.paused <- struct{}{}
+		if restart = <-p.resume; p.stopped {
+			return
+		}
+	}
+}
+
+// pause pauses the search, calls fn and resumes the search.
+//
+// The search is restarted if fn returns true or the search was not idle.
+func (p *Ponderer) pause(fn func() (restart bool)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		fn()
+		return
+	}
+	close(p.interrupt)
+	<-p.paused
+	restart := fn()
+	p.interrupt = make(chan struct{})
+	p.resume <- restart || !p.idle
+}
+
+// Play re-roots the tree onto the child matching action and continues searching.
+//
+// The statistics of the matching child's subtree are kept.
+// A new root is created when no such child has been opened.
+// Afterwards, Func receives actions relative to the new root.
+//
+// apply, if not nil, is called while the search is paused after re-rooting.
+// Use it to update state shared with Func.
+func (p *Ponderer) Play(action string, apply func()) {
+	p.pause(func() bool {
+		root := newRoot()
+		if i, ok := p.root.indexChild(action); ok {
+			if child := lazyq.At(p.root.Queue, i).Node; child != nil {
+				root = child
+				root.Parent = nil
+				root.Action = ""
+			}
+		}
+		p.root = root
+		p.result = Result{Root: root}
+		if apply != nil {
+			apply()
+		}
+		return true
+	})
+}
+
+// View pauses the search and calls fn with the current Result.
+//
+// fn may inspect the tree but must not retain it after returning.
+func (p *Ponderer) View(fn func(Result)) {
+	p.pause(func() bool { fn(p.result); return false })
+}
+
+// Result returns the current Result.
+//
+// Iterations and Duration are accumulated since the last Play.
+// The Root must not be accessed until the Ponderer is stopped. See View.
+func (p *Ponderer) Result() (result Result) {
+	p.View(func(r Result) { result = r })
+	return result
+}
+
+// Stop stops the search and returns the final Result.
+//
+// Stop may be called multiple times.
+func (p *Ponderer) Stop() Result {
+	p.pause(func() bool { p.stopped = true; return false })
+	<-p.done
+	return p.result
+}
@@ -0,0 +1,67 @@
+package mcts
+
+import (
+	"sync"
+	"testing"
+)
+
+// iterWaiter closes reached once a search reaches n iterations.
+type iterWaiter struct {
+	NopObserver
+	n       int
+	once    sync.Once
+	reached chan struct{}
+}
+
+func (w *iterWaiter) OnIteration(i int) {
+	if i+1 >= w.n {
+		w.once.Do(func() { close(w.reached) })
+	}
+}
+
+func TestPonderer(t *testing.T) {
+	var depth int // Moves played, updated by Play.
+	w := &iterWaiter{n: 100, reached: make(chan struct{})}
+	p := Ponder(func(c *Context) {
+		if depth+c.Len() < 6 {
+			c.Expand("a", "b")
+		}
+		c.SetResultValue(1)
+	}, Observe(w))
+
+	<-w.reached
+
+	var trials float32
+	p.View(func(r Result) {
+		if r.Iterations < w.n {
+			t.Errorf("TestPonderer(): got %d iterations, want at least %d", r.Iterations, w.n)
+			return
+		}
+		if n := extractVariation(r.Root, "a"); n != nil {
+			trials = n.Trials
+		}
+	})
+
+	p.Play("a", func() { depth++ })
+
+	r := p.Stop()
+	if r.Root.Parent != nil {
+		t.Errorf("TestPonderer(): got root with parent after Play, want nil parent")
+	}
+	if r.Root.Trials < trials {
+		t.Errorf("TestPonderer(): got %v trials at new root, want at least %v kept from the old subtree", r.Root.Trials, trials)
+	}
+	p.Stop() // Stop is idempotent.
+}
+
+func TestPondererContinuation(t *testing.T) {
+	root := Search(func(c *Context) {
+		c.Expand("a", "b")
+		c.SetResultValue(1)
+	}, MaxIters(10)).Root
+
+	p := Ponder(func(c *Context) { c.SetResultValue(1) }, UseContinuation(root), MaxIters(10))
+	if r := p.Stop(); r.Root != root {
+		t.Errorf("TestPondererContinuation(): got a new root, want the root given to UseContinuation")
+	}
+}
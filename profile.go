@@ -0,0 +1,120 @@
+package mcts
+
+import "time"
+
+// Phases holds the time spent in each phase of a search iteration.
+type Phases struct {
+	// Select is the time spent selecting the frontier node including OnSelect hooks.
+	Select time.Duration
+	// Run is the time spent in the user Func.
+	Run time.Duration
+	// Expand is the time spent shuffling and inserting expanded children including OnExpand hooks.
+	Expand time.Duration
+	// Backprop is the time spent updating bandits and fixing heaps including OnBackprop hooks.
+	Backprop time.Duration
+}
+
+// Total returns the sum of all phases.
+func (p Phases) Total() time.Duration { return p.Select + p.Run + p.Expand + p.Backprop }
+
+// PhaseProfile is a breakdown of where a search spent its time. See ProfilePhases.
+type PhaseProfile struct {
+	// Iterations is the number of iterations profiled.
+	Iterations int
+	// Sampled is the number of iterations whose phases were timed.
+	Sampled int
+	// Sum is the time spent in each phase summed over the sampled iterations.
+	Sum Phases
+	// PathLength is the mean depth of the selected frontier node over all iterations.
+	PathLength float64
+	// Expansions is the mean number of children added per iteration over all iterations.
+	Expansions float64
+}
+
+// Mean returns the mean time spent in each phase per sampled iteration.
+func (p *PhaseProfile) Mean() Phases {
+	if p.Sampled == 0 {
+		return Phases{}
+	}
+	n := time.Duration(p.Sampled)
+	return Phases{
+		Select:   p.Sum.Select / n,
+		Run:      p.Sum.Run / n,
+		Expand:   p.Sum.Expand / n,
+		Backprop: p.Sum.Backprop / n,
+	}
+}
+
+type phase int
+
+const (
+	phaseSelect phase = iota
+	phaseRun
+	phaseExpand
+	phaseBackprop
+)
+
+// profiler times the phases of every n-th iteration.
+//
+// Methods on a nil profiler do nothing so profiling costs a single branch per phase when disabled.
+type profiler struct {
+	every      int
+	sample     bool
+	last       time.Time
+	sum        [4]time.Duration
+	sampled    int
+	pathLength int
+	expansions int
+}
+
+// start starts timing the iteration iters if it is sampled.
+func (p *profiler) start(iters int) {
+	if p == nil {
+		return
+	}
+	if p.sample = iters%p.every == 0; p.sample {
+		p.sampled++
+		p.last = time.Now()
+	}
+}
+
+// lap adds the time since the last lap to phase if the iteration is sampled.
+func (p *profiler) lap(phase phase) {
+	if p == nil || !p.sample {
+		return
+	}
+	now := time.Now()
+	p.sum[phase] += now.Sub(p.last)
+	p.last = now
+}
+
+// count records the path length and number of expansions of an iteration.
+func (p *profiler) count(pathLength, expansions int) {
+	if p == nil {
+		return
+	}
+	p.pathLength += pathLength
+	p.expansions += expansions
+}
+
+// profile returns the PhaseProfile after iters iterations or nil if p is nil.
+func (p *profiler) profile(iters int) *PhaseProfile {
+	if p == nil {
+		return nil
+	}
+	prof := &PhaseProfile{
+		Iterations: iters,
+		Sampled:    p.sampled,
+		Sum: Phases{
+			Select:   p.sum[phaseSelect],
+			Run:      p.sum[phaseRun],
+			Expand:   p.sum[phaseExpand],
+			Backprop: p.sum[phaseBackprop],
+		},
+	}
+	if iters > 0 {
+		prof.PathLength = float64(p.pathLength) / float64(iters)
+		prof.Expansions = float64(p.expansions) / float64(iters)
+	}
+	return prof
+}
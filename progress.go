@@ -0,0 +1,81 @@
+package mcts
+
+import (
+	"time"
+
+	"github.com/ajzaff/lazyq"
+)
+
+// Snapshot summarizes the state of a running search.
+type Snapshot struct {
+	Iterations          int
+	IterationsPerSecond float64
+	Elapsed             time.Duration
+	// Best is the most visited child of root.
+	//
+	// Best is the zero Child if root has no children.
+	Best Child
+	// Line is the principal variation from root following the most visited child at each step.
+	//
+	// Line matches the line of variation.MostPopularVariation with ties broken by queue order.
+	Line []string
+}
+
+type progress struct {
+	every    time.Duration
+	iters    int
+	fn       func(Snapshot)
+	nextTime time.Time
+	nextIter int
+}
+
+func (p *progress) init(start time.Time) {
+	p.nextTime = start.Add(p.every)
+	p.nextIter = p.iters
+}
+
+// due reports whether a snapshot should be taken and schedules the next one.
+func (p *progress) due(iters int, now time.Time) bool {
+	if p.iters > 0 {
+		if iters < p.nextIter {
+			return false
+		}
+		p.nextIter = iters + p.iters
+		return true
+	}
+	if now.Before(p.nextTime) {
+		return false
+	}
+	p.nextTime = now.Add(p.every)
+	return true
+}
+
+// mostPopularChild returns the child of n with the most runs.
+func mostPopularChild(n *Node) (best Child, ok bool) {
+	for e := range lazyq.Payloads(n.Queue) {
+		if !ok || best.Runs < e.Runs {
+			best, ok = e, true
+		}
+	}
+	return best, ok
+}
+
+func newSnapshot(root *Node, iters int, elapsed time.Duration) Snapshot {
+	s := Snapshot{
+		Iterations: iters,
+		Elapsed:    elapsed,
+	}
+	if elapsed > 0 {
+		s.IterationsPerSecond = float64(iters) / elapsed.Seconds()
+	}
+	s.Best, _ = mostPopularChild(root)
+	for n := root; n != nil; {
+		e, ok := mostPopularChild(n)
+		if !ok || e.Node == nil {
+			break
+		}
+		s.Line = append(s.Line, e.Action)
+		n = e.Node
+	}
+	return s
+}
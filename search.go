@@ -13,6 +13,8 @@ type Result struct {
 	Iterations int
 	Duration   time.Duration
 	Err        error
+	// Profile is the breakdown of time spent per phase when ProfilePhases is used, otherwise nil.
+	Profile *PhaseProfile
 }
 
 // Search is the main function from this package which implements Monte-carlo tree search.
@@ -41,6 +43,13 @@ func Search(runFn Func, opts ...Option) (result Result) {
 	r := rand.New(searchOpts.src)
 	maxItersDefined := searchOpts.maxIters > 0
 	exploreFactor := searchOpts.exploreFactor
+	discount, window := searchOpts.discount, searchOpts.window
+	observers := searchOpts.observers
+	progress := searchOpts.progress
+	prof := searchOpts.profiler
+	for _, p := range progress {
+		p.init(start)
+	}
 
 	c := &Context{
 		actions: make([]string, 0, 64),
@@ -52,6 +61,7 @@ func Search(runFn Func, opts ...Option) (result Result) {
 		// 1. Select a frontier node with the maximum bandit at each step. Construct replay actions.
 		frontier := root
 		c.reset()
+		prof.start(iters)
 		for frontier.Exhausted() && frontier.Queue.Len() > 0 {
 			next := frontier.next()
 			if next.Node == nil {
@@ -60,9 +70,14 @@ func Search(runFn Func, opts ...Option) (result Result) {
 			c.actions = append(c.actions, next.Action)
 			frontier = next.Node
 		}
+		for _, o := range observers {
+			o.OnSelect(frontier, c.actions)
+		}
+		prof.lap(phaseSelect)
 
 		// 2. Run simulations at the frontier node.
 		runFn(c)
+		prof.lap(phaseRun)
 		frontier.Flags &= ^FlagsMinimize
 		frontier.Flags |= c.flags & FlagsMinimize
 
@@ -82,33 +97,62 @@ func Search(runFn Func, opts ...Option) (result Result) {
 			}
 			frontier.NewChild(action, prior)
 		}
+		if len(c.expand) > 0 {
+			for _, o := range observers {
+				o.OnExpand(frontier, c.expand, c.priors)
+			}
+		}
 
 		// 	2d. (optional) Keep the frontier node in the frontier set.
 		if c.flags.Exhausted() {
 			frontier.Flags |= FlagsExhausted
 		}
+		prof.lap(phaseExpand)
 
 		// 	2e. Backpropagate the results up the tree and fix the bandit heaps along the way.
 		for head := frontier.Parent; head != nil; head = head.Parent {
-			head.addValueRuns(c.value, c.count)
+			head.addValueRuns(c.value, c.count, discount, window)
 			// Recompute the PUCT policy value for the frontier.
 			bandit := lazyq.First(head.Queue)
 			head.Queue.Decrease(bandit.computePriority(head.logTrials()))
+			for _, o := range observers {
+				o.OnBackprop(head, c.value, c.count)
+			}
 		}
+		prof.lap(phaseBackprop)
+		prof.count(len(c.actions), len(c.expand))
 
 		// 	3. State keeping and termination.
+		for _, o := range observers {
+			o.OnIteration(iters)
+		}
 		iters++ //	3a. Increment iterations.
 
-		if searchOpts.done || c.done { //	3b. (optional) Stop search if done.
+		//	3b. (optional) Report progress.
+		if len(progress) > 0 {
+			now := time.Now()
+			for _, p := range progress {
+				if p.due(iters, now) {
+					p.fn(newSnapshot(root, iters, now.Sub(start)))
+				}
+			}
+		}
+
+		if searchOpts.done.Load() || c.done { //	3c. (optional) Stop search if done.
 			result.Err = c.err
 			break
 		}
 
-		//	3c. End the search when maxIters is reached.
+		//	3d. End the search when maxIters is reached.
 		if maxItersDefined && iters >= searchOpts.maxIters {
 			break
 		}
 
+		//	3e. (optional) Stop early when the time manager decides the search is over.
+		if tm := searchOpts.timeManager; tm != nil && tm.stop(root, iters, searchOpts.maxIters, time.Since(start)) {
+			break
+		}
+
 		// 4. Restart from step 1.
 	}
 
@@ -117,5 +161,7 @@ func Search(runFn Func, opts ...Option) (result Result) {
 		Root:       root,
 		Iterations: iters,
 		Duration:   time.Since(start),
+		Err:        result.Err,
+		Profile:    prof.profile(iters),
 	}
 }
@@ -27,19 +27,40 @@ func (s *Stat) Clear() {
 // addValueRuns adds val and runs to the top bandit Stat
 // and updates the node's Trials counter.
 //
+// When gamma is not 1, every child Stat and Trials are first discounted by gamma
+// so that the statistics of arms which are not selected fade as well (see Discount).
+// Like the growth of Trials, the discount reaches the priorities of other children
+// when they are next selected and updated. Discounting does not allocate.
+// The top Stat is then limited to window runs (see SlidingWindow).
+// Trials is adjusted by the net change in Runs so it remains the sum of its children's Runs.
+//
 // addValueRuns correctly handles the node's Minimize flag.
 //
 // We expect to call recomputePriority afterwards.
-func (n *Node) addValueRuns(val, runs float32) {
+func (n *Node) addValueRuns(val, runs, gamma, window float32) {
 	if n.Minimize() {
 		// Negate minimizing nodes (min(a,b) = -max(-a,-b)).
 		val = -val
 	}
+	if gamma != 1 {
+		for i, e := range lazyq.ElementIndices(n.Queue) {
+			e.E.Value *= gamma
+			e.E.Runs *= gamma
+			lazyq.ReplacePayload(n.Queue, i, e.E)
+		}
+		n.Trials *= gamma
+	}
 	e := lazyq.First(n.Queue)
+	prevRuns := e.Runs
 	e.Value += val
 	e.Runs += runs
+	if window > 0 && e.Runs > window {
+		// Rescale to the window preserving the mean.
+		e.Value *= window / e.Runs
+		e.Runs = window
+	}
 	lazyq.ReplacePayload(n.Queue, 0, e)
-	n.Trials += runs
+	n.Trials += e.Runs - prevRuns
 }
 
 // Score the stat on the node taking into account the Minimize flag.
@@ -66,6 +87,17 @@ func (s Stat) Score() float32 {
 // logTrials behavior is undefined when return n.Trials <= 0.
 func (n *Node) logTrials() float32 { return fastlog.Log(n.Trials + 1) }
 
+// Priority returns the bandit priority of the child c of n used to select the next child.
+//
+// Priority computes the PUCT formula using the current Trials of n.
+// Unopened children always take priority and return +Inf.
+func (n *Node) Priority(c Child) float32 {
+	if c.Node == nil {
+		return float32(math.Inf(+1))
+	}
+	return c.computePriority(n.logTrials())
+}
+
 // computePriority computes the PUCT formula on the inputs.
 func (s Stat) computePriority(logTrials float32) float32 {
 	runFactor := 1 / (s.Runs + 1)
@@ -0,0 +1,91 @@
+package mcts
+
+import (
+	"math"
+	"time"
+
+	"github.com/ajzaff/lazyq"
+)
+
+// TimeControl configures the time manager of a search. See TimeManager.
+//
+// All fields are optional.
+type TimeControl struct {
+	// Soft is the target duration of the search.
+	//
+	// The search stops after Soft unless the soft limit was extended.
+	Soft time.Duration
+	// Hard is the maximum duration of the search.
+	Hard time.Duration
+	// Extend is the fraction of Soft added to the soft limit each time the most visited root child changes.
+	//
+	// The soft limit is never extended beyond Hard.
+	Extend float64
+}
+
+type timeManager struct {
+	TimeControl
+	soft time.Duration
+	best string
+}
+
+// stop reports whether the search should stop after iters iterations.
+//
+// stop returns true when a time limit is reached or the most visited root child
+// can no longer be overtaken in the remaining iterations and time.
+func (m *timeManager) stop(root *Node, iters, maxIters int, elapsed time.Duration) bool {
+	if m.Hard > 0 && elapsed >= m.Hard {
+		return true
+	}
+
+	// Find the most visited and runner-up root children.
+	var (
+		best   Child
+		second float32
+		found  bool
+	)
+	for e := range lazyq.Payloads(root.Queue) {
+		switch {
+		case !found || best.Runs < e.Runs:
+			second = best.Runs
+			best, found = e, true
+		case second < e.Runs:
+			second = e.Runs
+		}
+	}
+	if !found {
+		return m.soft > 0 && elapsed >= m.soft
+	}
+
+	// Extend the soft limit when the best action is unstable.
+	if best.Action != m.best {
+		if m.best != "" && m.Extend > 0 {
+			m.soft += time.Duration(float64(m.Soft) * m.Extend)
+			if m.Hard > 0 && m.soft > m.Hard {
+				m.soft = m.Hard
+			}
+		}
+		m.best = best.Action
+	}
+	if m.soft > 0 && elapsed >= m.soft {
+		return true
+	}
+
+	// Estimate the runs remaining in the search.
+	remaining := math.Inf(+1)
+	if maxIters > 0 {
+		remaining = float64(maxIters - iters)
+	}
+	limit := m.Hard
+	if limit == 0 {
+		limit = m.soft
+	}
+	if limit > 0 && elapsed > 0 {
+		remaining = min(remaining, float64(iters)*float64(limit-elapsed)/float64(elapsed))
+	}
+	if math.IsInf(remaining, +1) || iters == 0 {
+		return false
+	}
+	runsPerIter := float64(root.Trials) / float64(iters)
+	return float64(best.Runs-second) > remaining*runsPerIter
+}
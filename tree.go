@@ -0,0 +1,239 @@
+package mcts
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/ajzaff/lazyq"
+)
+
+// treeMagic and treeVersion prefix the binary tree format written by WriteTree.
+const (
+	treeMagic   = "MCTS"
+	treeVersion = 1
+)
+
+// maxActionLen bounds the length of actions read by ReadTree.
+const maxActionLen = 1 << 20
+
+// ErrTreeVersion is returned from ReadTree when the tree was written by an unsupported version.
+var ErrTreeVersion = errors.New("unsupported tree version")
+
+// WriteTree writes the tree under root to w in a compact binary format.
+//
+// The format is versioned and stores the Action, Trials and Flags of every node
+// and the Action and Stat of every child, including unopened children.
+// Opened children are written with their bandit heap priorities in descending order of priority
+// followed by unopened children in queue order, so the output does not depend on the heap layout.
+// Parents of root are not written.
+func WriteTree(w io.Writer, root *Node) error {
+	bw := bufio.NewWriter(w)
+	tw := treeWriter{w: bw}
+	tw.buf = append(tw.buf, treeMagic...)
+	tw.buf = append(tw.buf, treeVersion)
+	tw.writeString(root.Action)
+	tw.writeNode(root)
+	tw.flush()
+	if tw.err != nil {
+		return tw.err
+	}
+	return bw.Flush()
+}
+
+type treeWriter struct {
+	w   *bufio.Writer
+	buf []byte
+	err error
+}
+
+func (w *treeWriter) flush() {
+	if w.err == nil {
+		_, w.err = w.w.Write(w.buf)
+	}
+	w.buf = w.buf[:0]
+}
+
+func (w *treeWriter) writeString(s string) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *treeWriter) writeFloat(f float32) {
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, math.Float32bits(f))
+}
+
+func (w *treeWriter) writeNode(n *Node) {
+	w.writeFloat(n.Trials)
+	w.buf = binary.AppendVarint(w.buf, int64(n.Flags))
+	size := n.Queue.Len()
+	opened := size
+	if lazyq.HasMaxElems(n.Queue) {
+		opened = lazyq.MaxIndex(n.Queue)
+	}
+	w.buf = binary.AppendUvarint(w.buf, uint64(size))
+	w.buf = binary.AppendUvarint(w.buf, uint64(opened))
+	elems := make([]lazyq.Elem[Child], 0, size)
+	for e := range lazyq.Elements(n.Queue) {
+		elems = append(elems, e)
+	}
+	slices.SortStableFunc(elems[:opened], func(a, b lazyq.Elem[Child]) int {
+		if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.E.Action, b.E.Action)
+	})
+	for i, elem := range elems {
+		e := elem.E
+		w.writeString(e.Action)
+		w.writeFloat(e.ExploreFactor)
+		w.writeFloat(e.Runs)
+		w.writeFloat(e.Value)
+		if i < opened {
+			w.writeFloat(elem.Priority)
+		}
+		if e.Node == nil {
+			w.buf = append(w.buf, 0)
+			continue
+		}
+		w.buf = append(w.buf, 1)
+		if len(w.buf) >= 4096 {
+			w.flush()
+		}
+		w.writeNode(e.Node)
+	}
+}
+
+// ReadTree reads a tree written by WriteTree from r and returns its root.
+//
+// Opened children are pushed into the bandit heap with their stored priorities,
+// so writing the restored tree produces the same bytes and a resumed search selects the same children first.
+// The root can be passed to UseContinuation to resume the search.
+func ReadTree(r io.Reader) (*Node, error) {
+	tr := treeReader{r: bufio.NewReader(r)}
+	var magic [len(treeMagic)]byte
+	if _, err := io.ReadFull(tr.r, magic[:]); err != nil || string(magic[:]) != treeMagic {
+		return nil, fmt.Errorf("invalid tree header %q", magic)
+	}
+	if version := tr.readByte(); tr.err == nil && version != treeVersion {
+		return nil, fmt.Errorf("%w: %d", ErrTreeVersion, version)
+	}
+	root := newRoot()
+	root.Action = tr.readString()
+	tr.readNode(root)
+	if tr.err != nil {
+		if tr.err == io.EOF {
+			tr.err = io.ErrUnexpectedEOF
+		}
+		return nil, tr.err
+	}
+	return root, nil
+}
+
+type treeReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (r *treeReader) readByte() byte {
+	if r.err != nil {
+		return 0
+	}
+	var b byte
+	b, r.err = r.r.ReadByte()
+	return b
+}
+
+func (r *treeReader) readUvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	var x uint64
+	x, r.err = binary.ReadUvarint(r.r)
+	return x
+}
+
+func (r *treeReader) readString() string {
+	n := r.readUvarint()
+	if r.err != nil {
+		return ""
+	}
+	if n > maxActionLen {
+		r.err = fmt.Errorf("action length %d exceeds limit %d", n, maxActionLen)
+		return ""
+	}
+	b := make([]byte, n)
+	_, r.err = io.ReadFull(r.r, b)
+	return string(b)
+}
+
+func (r *treeReader) readFloat() float32 {
+	if r.err != nil {
+		return 0
+	}
+	var b [4]byte
+	_, r.err = io.ReadFull(r.r, b[:])
+	return math.Float32frombits(binary.LittleEndian.Uint32(b[:]))
+}
+
+func (r *treeReader) readNode(n *Node) {
+	n.Trials = r.readFloat()
+	if r.err == nil {
+		var flags int64
+		flags, r.err = binary.ReadVarint(r.r)
+		n.Flags = Flags(flags)
+	}
+	size, opened := r.readUvarint(), r.readUvarint()
+	if r.err != nil {
+		return
+	}
+	if opened > size {
+		r.err = fmt.Errorf("opened children %d exceeds children %d", opened, size)
+		return
+	}
+	// Avoid trusting large sizes up front. The queue grows as children are read.
+	lazyq.Grow(&n.Queue, int(min(size, 1024)))
+	priorities := make([]float32, 0, min(opened, 1024))
+	for i := range size {
+		child := Child{Action: r.readString()}
+		child.ExploreFactor = r.readFloat()
+		child.Runs = r.readFloat()
+		child.Value = r.readFloat()
+		if i < opened {
+			priorities = append(priorities, r.readFloat())
+		}
+		switch b := r.readByte(); {
+		case r.err != nil:
+			return
+		case b == 1:
+			child.Node = &Node{Parent: n, Action: child.Action}
+			r.readNode(child.Node)
+		case b != 0:
+			r.err = fmt.Errorf("invalid child marker %d", b)
+		}
+		if r.err != nil {
+			return
+		}
+		n.Queue.AppendMax(child)
+	}
+	// Opened children are read in descending order of priority and are pushed in the same order.
+	for _, p := range priorities {
+		n.Queue.Next()
+		n.Queue.Decrease(p)
+	}
+}
+
+// restoreOpened moves the first opened children of n into the bandit heap
+// with priorities recomputed from their Stat.
+func (n *Node) restoreOpened(opened int) {
+	logTrials := n.logTrials()
+	for range opened {
+		e := n.Queue.Next()
+		n.Queue.Decrease(e.computePriority(logTrials))
+	}
+}
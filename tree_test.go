@@ -0,0 +1,145 @@
+package mcts
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ajzaff/lazyq"
+)
+
+func compareTrees(t *testing.T, line []string, got, want *Node) {
+	t.Helper()
+	if got.Action != want.Action || got.Trials != want.Trials || got.Flags != want.Flags {
+		t.Errorf("ReadTree(): node %v got (%q, %v, %v), want (%q, %v, %v)", line,
+			got.Action, got.Trials, got.Flags, want.Action, want.Trials, want.Flags)
+	}
+	if got.Queue.Len() != want.Queue.Len() {
+		t.Fatalf("ReadTree(): node %v got %d children, want %d", line, got.Queue.Len(), want.Queue.Len())
+	}
+	for w := range lazyq.Payloads(want.Queue) {
+		g, ok := lookupElem(got, w.Action)
+		if !ok {
+			t.Fatalf("ReadTree(): node %v missing child %q", line, w.Action)
+		}
+		if g.E.Stat != w.Stat {
+			t.Errorf("ReadTree(): child %v got stat %v, want %v", append(line, w.Action), g.E.Stat, w.Stat)
+		}
+		if (g.E.Node == nil) != (w.Node == nil) {
+			t.Fatalf("ReadTree(): child %v got opened = %v, want %v", append(line, w.Action), g.E.Node != nil, w.Node != nil)
+		}
+		if w.Node != nil {
+			if g.E.Node.Parent != got {
+				t.Errorf("ReadTree(): child %v has wrong parent", append(line, w.Action))
+			}
+			compareTrees(t, append(line, w.Action), g.E.Node, w.Node)
+		}
+	}
+}
+
+// compareTreesExact compares got and want child by child, including heap priorities of opened children,
+// the first child of each queue and the order of unopened children.
+func compareTreesExact(t *testing.T, line []string, got, want *Node) {
+	t.Helper()
+	if got.Action != want.Action || got.Trials != want.Trials || got.Flags != want.Flags {
+		t.Errorf("ReadTree(): node %v got (%q, %v, %v), want (%q, %v, %v)", line,
+			got.Action, got.Trials, got.Flags, want.Action, want.Trials, want.Flags)
+	}
+	if got.Queue.Len() != want.Queue.Len() || lazyq.MaxIndex(got.Queue) != lazyq.MaxIndex(want.Queue) {
+		t.Fatalf("ReadTree(): node %v got %d children with %d opened, want %d with %d opened", line,
+			got.Queue.Len(), lazyq.MaxIndex(got.Queue), want.Queue.Len(), lazyq.MaxIndex(want.Queue))
+	}
+	opened := lazyq.MaxIndex(want.Queue)
+	if opened > 0 {
+		g, _ := lookupElem(got, lazyq.First(got.Queue).Action)
+		w, _ := lookupElem(want, lazyq.First(want.Queue).Action)
+		if g.Priority != w.Priority {
+			t.Errorf("ReadTree(): node %v got first priority %v, want %v", line, g.Priority, w.Priority)
+		}
+	}
+	for i, w := range lazyq.ElementIndices(want.Queue) {
+		if i >= opened {
+			if g := lazyq.At(got.Queue, i); g.Action != w.E.Action {
+				t.Errorf("ReadTree(): node %v unopened child %d got %q, want %q", line, i, g.Action, w.E.Action)
+			}
+		}
+		g, ok := lookupElem(got, w.E.Action)
+		if !ok {
+			t.Fatalf("ReadTree(): node %v missing child %q", line, w.E.Action)
+		}
+		if g.E.Stat != w.E.Stat {
+			t.Errorf("ReadTree(): child %v got stat %v, want %v", append(line, w.E.Action), g.E.Stat, w.E.Stat)
+		}
+		if i < opened && g.Priority != w.Priority {
+			t.Errorf("ReadTree(): child %v got priority %v, want %v", append(line, w.E.Action), g.Priority, w.Priority)
+		}
+		if (g.E.Node == nil) != (w.E.Node == nil) {
+			t.Fatalf("ReadTree(): child %v got opened = %v, want %v", append(line, w.E.Action), g.E.Node != nil, w.E.Node != nil)
+		}
+		if w.E.Node != nil {
+			if g.E.Node.Parent != got {
+				t.Errorf("ReadTree(): child %v has wrong parent", append(line, w.E.Action))
+			}
+			compareTreesExact(t, append(line, w.E.Action), g.E.Node, w.E.Node)
+		}
+	}
+}
+
+func TestWriteReadTree(t *testing.T) {
+	results := Search(func(c *Context) {
+		if c.Len() < 4 {
+			c.Expand("a", "b", "c")
+		}
+		if c.Len()%2 == 1 {
+			c.Minimize()
+		}
+		c.SetResultValue(float32(len(c.ActionAt(0))))
+	}, MaxIters(200))
+
+	var buf bytes.Buffer
+	if err := WriteTree(&buf, results.Root); err != nil {
+		t.Fatalf("WriteTree(): got err = %v, want nil", err)
+	}
+	data := buf.Bytes()
+	root, err := ReadTree(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadTree(): got err = %v, want nil", err)
+	}
+	compareTreesExact(t, nil, root, results.Root)
+
+	// Writing the restored tree produces the same bytes.
+	var buf2 bytes.Buffer
+	if err := WriteTree(&buf2, root); err != nil {
+		t.Fatalf("WriteTree(ReadTree()): got err = %v, want nil", err)
+	}
+	if !bytes.Equal(buf2.Bytes(), data) {
+		t.Errorf("WriteTree(ReadTree()): got %d bytes differing from the original %d bytes", buf2.Len(), len(data))
+	}
+
+	// Resuming the restored tree and a tree restored from its output makes the same choices.
+	root2, err := ReadTree(bytes.NewReader(buf2.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadTree(WriteTree(ReadTree())): got err = %v, want nil", err)
+	}
+	resume := func(root *Node) []string {
+		var lines []string
+		Search(func(c *Context) {
+			lines = append(lines, strings.Join(slices.Collect(c.Actions()), "/"))
+			c.SetResultValue(float32(c.Len() % 2))
+		}, MaxIters(50), UseContinuation(root))
+		return lines
+	}
+	if got, want := resume(root2), resume(root); !slices.Equal(got, want) {
+		t.Errorf("Search(UseContinuation(ReadTree())): got lines %v, want %v", got, want)
+	}
+
+	if _, err := ReadTree(bytes.NewReader(data[:len(data)/2])); err == nil {
+		t.Errorf("ReadTree(truncated): got err = nil, want error")
+	}
+	data[4] = treeVersion + 1
+	if _, err := ReadTree(bytes.NewReader(data)); !errors.Is(err, ErrTreeVersion) {
+		t.Errorf("ReadTree(bad version): got err = %v, want %v", err, ErrTreeVersion)
+	}
+}
@@ -0,0 +1,107 @@
+// Package viz renders search trees for visualization.
+package viz
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/ajzaff/lazyq"
+	"github.com/ajzaff/mcts"
+	"github.com/ajzaff/mcts/variation"
+)
+
+// Options controls pruning of rendered trees.
+type Options struct {
+	// MaxDepth limits the depth of nodes rendered below root.
+	// The default 0 renders all depths.
+	MaxDepth int
+	// MinRuns prunes children with fewer than MinRuns runs.
+	MinRuns float32
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+type dotWriter struct {
+	w    *bufio.Writer
+	opts Options
+	pv   map[*mcts.Node]bool
+	next int
+}
+
+// WriteDOT writes the tree under root to w as a Graphviz DOT graph.
+//
+// Nodes are labeled with their action, runs, mean score and flags.
+// Edge thickness is proportional to the child's share of its parent's trials.
+// The principal variation (see variation.MostPopularVariation) is highlighted.
+func WriteDOT(w io.Writer, root *mcts.Node, opts Options) error {
+	d := dotWriter{w: bufio.NewWriter(w), opts: opts, pv: make(map[*mcts.Node]bool)}
+	for n := variation.MostPopularVariation(root, rand.New(rand.NewPCG(0, 0))); n != nil; n = n.Parent {
+		d.pv[n] = true
+	}
+	fmt.Fprintln(d.w, "digraph mcts {")
+	fmt.Fprintln(d.w, `	node [shape=box, fontname="monospace"];`)
+	fmt.Fprintf(d.w, "\tn0 [label=\"root\\ntrials %g%s\", style=bold];\n", root.Trials, flagsLabel(root))
+	d.next = 1
+	d.writeChildren(root, "n0", 0)
+	fmt.Fprintln(d.w, "}")
+	return d.w.Flush()
+}
+
+func flagsLabel(n *mcts.Node) string {
+	var sb strings.Builder
+	if n.Minimize() {
+		sb.WriteString(`\nminimize`)
+	}
+	if n.Exhausted() {
+		sb.WriteString(`\nexhausted`)
+	}
+	return sb.String()
+}
+
+func (d *dotWriter) writeChildren(n *mcts.Node, id string, depth int) {
+	if d.opts.MaxDepth > 0 && depth >= d.opts.MaxDepth {
+		return
+	}
+	for c := range lazyq.Payloads(n.Queue) {
+		if c.Runs < d.opts.MinRuns {
+			continue
+		}
+		childID := fmt.Sprintf("n%d", d.next)
+		d.next++
+
+		label := fmt.Sprintf(`%s\nruns %g`, labelEscaper.Replace(c.Action), c.Runs)
+		if c.Runs > 0 {
+			label += fmt.Sprintf(`\nscore %.4g`, c.Stat.Score())
+		}
+		var style string
+		switch {
+		case c.Node == nil:
+			style = "dashed"
+		case d.pv[c.Node]:
+			style = "bold,filled"
+		default:
+			style = "solid"
+		}
+		if c.Node != nil {
+			label += flagsLabel(c.Node)
+		}
+		fmt.Fprintf(d.w, "\t%s [label=\"%s\", style=\"%s\"];\n", childID, label, style)
+
+		var share float32
+		if n.Trials > 0 {
+			share = c.Runs / n.Trials
+		}
+		color := "black"
+		if c.Node != nil && d.pv[c.Node] {
+			color = "red"
+		}
+		fmt.Fprintf(d.w, "\t%s -> %s [penwidth=%.2f, color=%s];\n", id, childID, 1+4*share, color)
+
+		if c.Node != nil {
+			d.writeChildren(c.Node, childID, depth+1)
+		}
+	}
+}
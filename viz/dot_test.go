@@ -0,0 +1,117 @@
+package viz
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ajzaff/lazyq"
+	"github.com/ajzaff/mcts"
+	"github.com/ajzaff/mcts/variation"
+)
+
+func testTree() *mcts.Node {
+	return mcts.Search(func(c *mcts.Context) {
+		if c.Len() < 3 {
+			c.Expand(`q"`, `b\s`, "c")
+		}
+		var v float32
+		if c.Len() > 0 && c.ActionAt(0) == `q"` {
+			v = 1
+		}
+		c.SetResultValue(v)
+	}, mcts.MaxIters(200)).Root
+}
+
+func writeDOT(t *testing.T, root *mcts.Node, opts Options) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := WriteDOT(&sb, root, opts); err != nil {
+		t.Fatalf("WriteDOT(): got err = %v, want nil", err)
+	}
+	return sb.String()
+}
+
+// nodeStyle reports whether the node statement for id in out has the given style.
+func nodeStyle(out, id, style string) bool {
+	for line := range strings.Lines(out) {
+		if strings.HasPrefix(line, "\t"+id+" [label=") {
+			return strings.HasSuffix(line, fmt.Sprintf("style=%q];\n", style))
+		}
+	}
+	return false
+}
+
+func TestWriteDOTRootChildren(t *testing.T) {
+	root := testTree()
+	out := writeDOT(t, root, Options{MaxDepth: 1})
+
+	if !strings.HasPrefix(out, "digraph mcts {\n") || !strings.HasSuffix(out, "}\n") {
+		t.Errorf("WriteDOT(): got %q, want a digraph", out)
+	}
+	for _, label := range []string{`label="q\"\nruns `, `label="b\\s\nruns `, `label="c\nruns `} {
+		if !strings.Contains(out, label) {
+			t.Errorf("WriteDOT(): got %q, want escaped label %s", out, label)
+		}
+	}
+
+	var best mcts.Child
+	for c := range lazyq.Payloads(root.Queue) {
+		if best.Runs < c.Runs {
+			best = c
+		}
+	}
+	var edges int
+	for i, c := range lazyq.ElementIndices(root.Queue) {
+		id := fmt.Sprintf("n%d", i+1)
+		color, style := "black", "solid"
+		if c.E.Action == best.Action {
+			color, style = "red", "bold,filled"
+		}
+		edge := fmt.Sprintf("\tn0 -> %s [penwidth=%.2f, color=%s];\n", id, 1+4*c.E.Runs/root.Trials, color)
+		if !strings.Contains(out, edge) {
+			t.Errorf("WriteDOT(): got %q, want edge %q", out, edge)
+		}
+		if !nodeStyle(out, id, style) {
+			t.Errorf("WriteDOT(): got %q, want node %s with style %q", out, id, style)
+		}
+		edges++
+	}
+	if got := strings.Count(out, " -> "); got != edges {
+		t.Errorf("WriteDOT(MaxDepth=1): got %d edges, want %d", got, edges)
+	}
+}
+
+func TestWriteDOTPrincipalVariation(t *testing.T) {
+	root := testTree()
+	out := writeDOT(t, root, Options{})
+
+	line := variation.Line(variation.MostPopularVariation(root, rand.New(rand.NewPCG(0, 0))))
+	if got := strings.Count(out, "color=red"); got != len(line) {
+		t.Errorf("WriteDOT(): got %d highlighted edges, want %d for line %v", got, len(line), line)
+	}
+	if got := strings.Count(out, `style="bold,filled"`); got != len(line) {
+		t.Errorf("WriteDOT(): got %d highlighted nodes, want %d for line %v", got, len(line), line)
+	}
+}
+
+var runsRe = regexp.MustCompile(`\\nruns ([^\\"]+)`)
+
+func TestWriteDOTMinRuns(t *testing.T) {
+	root := testTree()
+	all := writeDOT(t, root, Options{})
+	pruned := writeDOT(t, root, Options{MinRuns: 10})
+
+	if strings.Count(pruned, " -> ") >= strings.Count(all, " -> ") {
+		t.Errorf("WriteDOT(MinRuns=10): got %d edges, want fewer than %d", strings.Count(pruned, " -> "), strings.Count(all, " -> "))
+	}
+	for _, m := range runsRe.FindAllStringSubmatch(pruned, -1) {
+		runs, err := strconv.ParseFloat(m[1], 32)
+		if err != nil || runs < 10 {
+			t.Errorf("WriteDOT(MinRuns=10): got node with runs %q, want at least 10", m[1])
+		}
+	}
+}